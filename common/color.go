@@ -0,0 +1,71 @@
+package common
+
+import (
+	"os"
+	"sync"
+
+	"github.com/muesli/termenv"
+)
+
+var (
+	profileOnce     sync.Once
+	detectedProfile termenv.Profile
+	profileOverride *termenv.Profile
+)
+
+// SetColorProfile overrides the detected color profile process-wide, the
+// components without their own ColorProfile use it(termenv.Ascii disables
+// all colors and styles)
+func SetColorProfile(p termenv.Profile) {
+	profileOverride = &p
+}
+
+// ColorProfile returns the process-wide color profile, it is detected once from
+// the terminal and respects NO_COLOR and CLICOLOR/CLICOLOR_FORCE, unless it is
+// overridden by SetColorProfile; there are no colors in the plain-text mode(see
+// IsPlain) unless CLICOLOR_FORCE is set
+func ColorProfile() termenv.Profile {
+	if profileOverride != nil {
+		return *profileOverride
+	}
+	if IsPlain() && !colorForced() {
+		return termenv.Ascii
+	}
+	profileOnce.Do(func() {
+		detectedProfile = termenv.EnvColorProfile()
+	})
+	return detectedProfile
+}
+
+// colorForced determine whether CLICOLOR_FORCE forces the colors
+func colorForced() bool {
+	forced := os.Getenv("CLICOLOR_FORCE")
+	return forced != "" && forced != "0"
+}
+
+// ProfileOrDefault returns the given color profile, or the process-wide
+// color profile if it is nil
+func ProfileOrDefault(p *termenv.Profile) termenv.Profile {
+	if p == nil {
+		return ColorProfile()
+	}
+	return *p
+}
+
+// FontColorWithProfile sets the color of the given string and bolds the font
+// with the given color profile, the string is returned as is for termenv.Ascii
+func FontColorWithProfile(p termenv.Profile, str, color string) string {
+	if p == termenv.Ascii {
+		return str
+	}
+	return termenv.String(str).Foreground(p.Color(color)).Bold().String()
+}
+
+// ColorWithProfile sets the color of the given string without bolding the font
+// with the given color profile, the string is returned as is for termenv.Ascii
+func ColorWithProfile(p termenv.Profile, str, color string) string {
+	if p == termenv.Ascii {
+		return str
+	}
+	return termenv.String(str).Foreground(p.Color(color)).String()
+}
@@ -2,16 +2,20 @@ package common
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/muesli/termenv"
 )
 
 const DONE = "DONE"
 
-var term = termenv.ColorProfile()
-
-// FontColor sets the color of the given string and bolds the font
+// FontColor sets the color of the given string and bolds the font, it
+// uses the process-wide color profile(see ColorProfile)
 func FontColor(str, color string) string {
-	return termenv.String(str).Foreground(term.Color(color)).Bold().String()
+	return FontColorWithProfile(ColorProfile(), str, color)
+}
+
+// Color sets the color of the given string without bolding the font, it
+// uses the process-wide color profile(see ColorProfile)
+func Color(str, color string) string {
+	return ColorWithProfile(ColorProfile(), str, color)
 }
 
 // GenSpaces generate a space string of specified length
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"unicode"
+)
+
+const (
+	// fuzzyMatchScore the basic score of each matched rune
+	fuzzyMatchScore = 16
+	// fuzzyConsecutiveBonus the bonus score of matched runes next to each other
+	fuzzyConsecutiveBonus = 24
+	// fuzzyBoundaryBonus the bonus score of matched runes at the start of a word
+	fuzzyBoundaryBonus = 32
+	// fuzzyGapPenalty the penalty score of each skipped rune
+	fuzzyGapPenalty = 1
+)
+
+// FuzzyMatch reports whether all the runes of the pattern appear in str in the
+// same order (case-insensitive), it returns the rune indexes of str that are
+// matched and a score, the higher the score, the better the match
+func FuzzyMatch(pattern, str string) (matched []int, score int, ok bool) {
+	p := []rune(pattern)
+	if len(p) == 0 {
+		return nil, 0, true
+	}
+
+	pi := 0
+	prev := -1
+	var last rune
+	for i, r := range []rune(str) {
+		if pi < len(p) && unicode.ToLower(r) == unicode.ToLower(p[pi]) {
+			score += fuzzyMatchScore
+			switch {
+			case prev >= 0 && prev == i-1:
+				score += fuzzyConsecutiveBonus
+			case i == 0 || isBoundary(last, r):
+				score += fuzzyBoundaryBonus
+			}
+			if prev >= 0 {
+				score -= (i - prev - 1) * fuzzyGapPenalty
+			}
+			matched = append(matched, i)
+			prev = i
+			pi++
+		}
+		last = r
+	}
+
+	if pi < len(p) {
+		return nil, 0, false
+	}
+	return matched, score, true
+}
+
+// isBoundary determine whether the current rune is the beginning of a word
+func isBoundary(last, cur rune) bool {
+	if !unicode.IsLetter(last) && !unicode.IsDigit(last) {
+		return true
+	}
+	return unicode.IsLower(last) && unicode.IsUpper(cur)
+}
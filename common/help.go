@@ -0,0 +1,140 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+)
+
+const (
+	DefaultShortSeparator = " • "
+	DefaultFullSeparator  = "    "
+
+	// Deprecated: use Theme.HelpKey instead
+	ColorHelpKey = "246"
+	// Deprecated: use Theme.HelpDesc instead
+	ColorHelpDesc = "241"
+	// Deprecated: use Theme.HelpSep instead
+	ColorHelpSep = "239"
+)
+
+// HelpKeyMap is implemented by the components that can render the help
+// information of their active key bindings
+type HelpKeyMap interface {
+	// ShortHelp returns the bindings displayed in the short help view
+	ShortHelp() []Binding
+	// FullHelp returns the bindings displayed in the expanded help view,
+	// each group is rendered as a column
+	FullHelp() [][]Binding
+}
+
+// Help renders the help view of the key bindings, the disabled bindings
+// and the bindings without help information are skipped
+type Help struct {
+	// ShowAll switches between the short and the expanded help view
+	ShowAll bool
+	// ShortSeparator the separator between the bindings of the short help view
+	ShortSeparator string
+	// FullSeparator the separator between the columns of the expanded help view
+	FullSeparator string
+	// Theme the colors of the help view, defaults to DefaultTheme()
+	Theme *Theme
+	// Profile the color profile of the help view, defaults to ColorProfile()
+	Profile *termenv.Profile
+}
+
+// View renders the short or the expanded help view according to ShowAll
+func (h Help) View(k HelpKeyMap) string {
+	if h.ShowAll {
+		return h.FullHelpView(k.FullHelp())
+	}
+	return h.ShortHelpView(k.ShortHelp())
+}
+
+// ShortHelpView renders the bindings in a single line
+func (h Help) ShortHelpView(bindings []Binding) string {
+	sep := h.ShortSeparator
+	if sep == "" {
+		sep = DefaultShortSeparator
+	}
+
+	theme := ThemeOrDefault(h.Theme)
+	var items []string
+	for _, b := range bindings {
+		if !b.Enabled() || b.Help().Key == "" {
+			continue
+		}
+		items = append(items, h.colorFg(b.Help().Key, theme.HelpKey)+" "+h.colorFg(b.Help().Desc, theme.HelpDesc))
+	}
+	return strings.Join(items, h.colorFg(sep, theme.HelpSep))
+}
+
+// FullHelpView renders each group of bindings as a column, the keys
+// and the descriptions of a column are aligned
+func (h Help) FullHelpView(groups [][]Binding) string {
+	sep := h.FullSeparator
+	if sep == "" {
+		sep = DefaultFullSeparator
+	}
+
+	theme := ThemeOrDefault(h.Theme)
+	var columns [][]string
+	var widths []int
+	for _, group := range groups {
+		var keys, descs []string
+		var keyWidth, descWidth int
+		for _, b := range group {
+			if !b.Enabled() || b.Help().Key == "" {
+				continue
+			}
+			keys = append(keys, b.Help().Key)
+			descs = append(descs, b.Help().Desc)
+			keyWidth = max(keyWidth, runewidth.StringWidth(b.Help().Key))
+			descWidth = max(descWidth, runewidth.StringWidth(b.Help().Desc))
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		column := make([]string, len(keys))
+		for i := range keys {
+			column[i] = h.colorFg(runewidth.FillRight(keys[i], keyWidth), theme.HelpKey) + " " +
+				h.colorFg(runewidth.FillRight(descs[i], descWidth), theme.HelpDesc)
+		}
+		columns = append(columns, column)
+		widths = append(widths, keyWidth+descWidth+1)
+	}
+
+	var rows int
+	for _, column := range columns {
+		rows = max(rows, len(column))
+	}
+
+	lines := make([]string, rows)
+	for i := range lines {
+		var cells []string
+		for j, column := range columns {
+			// the shorter columns are padded with spaces to keep the following columns aligned
+			if i < len(column) {
+				cells = append(cells, column[i])
+			} else {
+				cells = append(cells, GenSpaces(widths[j]))
+			}
+		}
+		lines[i] = strings.TrimRight(strings.Join(cells, sep), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// colorFg sets the color of the given string without bolding the font
+func (h Help) colorFg(str, color string) string {
+	return ColorWithProfile(ProfileOrDefault(h.Profile), str, color)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,133 @@
+package common
+
+import (
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyHelp is the help information of a key binding
+type KeyHelp struct {
+	// Key is the key name displayed in the help view, e.g. "↑/k"
+	Key string
+	// Desc is the description of the action, e.g. "move up"
+	Desc string
+}
+
+// Binding describes a set of keys bound to an action, it follows the key
+// binding concept of charmbracelet/bubbles: the components only match the
+// key messages against the bindings, so the callers can rebind or disable
+// any key without touching the component logic.
+type Binding struct {
+	keys     []string
+	help     KeyHelp
+	disabled bool
+}
+
+// BindingOpt is the option used to initialize a Binding
+type BindingOpt func(*Binding)
+
+// NewBinding returns a new Binding initialized with the given options
+func NewBinding(opts ...BindingOpt) Binding {
+	b := &Binding{}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return *b
+}
+
+// WithKeys sets the keys of the binding, the key names are the same as
+// tea.KeyMsg.String(), e.g. "ctrl+c", "pgdown", "q"
+func WithKeys(keys ...string) BindingOpt {
+	return func(b *Binding) {
+		b.keys = keys
+	}
+}
+
+// WithHelp sets the help information of the binding
+func WithHelp(key, desc string) BindingOpt {
+	return func(b *Binding) {
+		b.help = KeyHelp{Key: key, Desc: desc}
+	}
+}
+
+// WithDisabled disables the binding
+func WithDisabled() BindingOpt {
+	return func(b *Binding) {
+		b.disabled = true
+	}
+}
+
+// SetKeys sets the keys of the binding
+func (b *Binding) SetKeys(keys ...string) {
+	b.keys = keys
+}
+
+// Keys return the keys of the binding
+func (b Binding) Keys() []string {
+	return b.keys
+}
+
+// SetHelp sets the help information of the binding
+func (b *Binding) SetHelp(key, desc string) {
+	b.help = KeyHelp{Key: key, Desc: desc}
+}
+
+// Help return the help information of the binding
+func (b Binding) Help() KeyHelp {
+	return b.help
+}
+
+// SetEnabled enables or disables the binding
+func (b *Binding) SetEnabled(v bool) {
+	b.disabled = !v
+}
+
+// Enabled determine whether the binding is enabled and has at least one key
+func (b Binding) Enabled() bool {
+	return !b.disabled && len(b.keys) > 0
+}
+
+// Unbind removes all keys and the help information of the binding
+func (b *Binding) Unbind() {
+	b.keys = nil
+	b.help = KeyHelp{}
+}
+
+// WithoutRuneKeys return a copy of the binding without the single-character
+// keys (e.g. "q", "j", "1"), the given keys are kept; it is useful when the
+// printable characters are used as text input
+func (b Binding) WithoutRuneKeys(keep ...string) Binding {
+	keys := make([]string, 0, len(b.keys))
+	for _, k := range b.keys {
+		if utf8.RuneCountInString(k) != 1 || contains(keep, k) {
+			keys = append(keys, k)
+		}
+	}
+	b.keys = keys
+	return b
+}
+
+// Matches determine whether the key message matches any of the enabled bindings
+func Matches(msg tea.KeyMsg, bindings ...Binding) bool {
+	k := msg.String()
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		if contains(b.keys, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains determine whether the string slice contains the given string
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-isatty"
+)
+
+// PlainMode decides whether the components run in the plain-text mode, the
+// plain-text mode reads the answers line by line and prints the output
+// without any ANSI escape sequence, it is intended for CI and pipes
+type PlainMode int
+
+const (
+	// PlainAuto enables the plain-text mode if stdin or stdout is not a terminal
+	PlainAuto PlainMode = iota
+	// PlainAlways always enables the plain-text mode
+	PlainAlways
+	// PlainNever never enables the plain-text mode
+	PlainNever
+)
+
+// ErrNoInput is returned in the plain-text mode when the input has no more lines
+var ErrNoInput = errors.New("no input: stdin is not a terminal and has no more lines to read the answer from")
+
+var (
+	plainMode = PlainAuto
+	// plainOnce detects the plain-text mode once, the result of the detection is plainDetected
+	plainOnce     sync.Once
+	plainDetected bool
+)
+
+// ansiSeq matches the ANSI escape sequences(CSI and OSC)
+var ansiSeq = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+// SetPlainMode overrides the automatic detection of the plain-text mode
+func SetPlainMode(mode PlainMode) {
+	plainMode = mode
+}
+
+// IsPlain determine whether the components should run in the plain-text mode,
+// the Run methods of the components and the default color profile follow it
+func IsPlain() bool {
+	switch plainMode {
+	case PlainAlways:
+		return true
+	case PlainNever:
+		return false
+	}
+	plainOnce.Do(func() {
+		plainDetected = os.Getenv("TERM") == "dumb" || !isTerminal(os.Stdin) || !isTerminal(os.Stdout)
+	})
+	return plainDetected
+}
+
+// isTerminal determine whether the file is a terminal
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// StripANSI removes the ANSI escape sequences from the given string
+func StripANSI(s string) string {
+	return ansiSeq.ReplaceAllString(s, "")
+}
+
+// ReadLine reads a line from r without the line terminator; it reads one byte
+// at a time, so the following lines are left for the next component sharing r
+func ReadLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err == io.EOF {
+			if len(line) == 0 {
+				return "", ErrNoInput
+			}
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimRight(string(line), "\r"), nil
+}
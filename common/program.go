@@ -0,0 +1,42 @@
+package common
+
+import (
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// program adapts a component to a bubbletea program, it quits when the
+// component returns the DONE message
+type program struct {
+	update func(tea.Msg) tea.Cmd
+	view   func() string
+}
+
+// Init initializes the component, the components are initialized by their first Update
+func (p program) Init() tea.Cmd {
+	return p.update(nil)
+}
+
+// Update passes the message to the component
+func (p program) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg == DONE {
+		return p, tea.Quit
+	}
+	return p, p.update(msg)
+}
+
+// View renders the component
+func (p program) View() string {
+	return p.view()
+}
+
+// RunProgram runs a component until it finishes: in a bubbletea program on a
+// terminal, or through runPlain with stdin and stdout in the plain-text mode(see IsPlain)
+func RunProgram(update func(tea.Msg) tea.Cmd, view func() string, runPlain func(in io.Reader, out io.Writer) error) error {
+	if IsPlain() {
+		return runPlain(os.Stdin, os.Stdout)
+	}
+	return tea.NewProgram(program{update: update, view: view}).Start()
+}
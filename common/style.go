@@ -0,0 +1,78 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// sgrSeq matches the SGR sequences(colors and text attributes)
+var sgrSeq = regexp.MustCompile(`\x1b\[([0-9;]*)m`)
+
+// RenderStyle renders the string with the lipgloss style and the given color
+// profile: the colors are converted to the profile(e.g. the true colors are
+// downsampled to 256 or 16 colors), and the colors and the text attributes are
+// stripped for termenv.Ascii(e.g. NO_COLOR is set), while the layout of the
+// style(padding, borders, width...) is kept. lipgloss renders the style with the
+// profile it detects, so the colors can not exceed that profile
+func RenderStyle(p termenv.Profile, style lipgloss.Style, str string) string {
+	switch p {
+	case termenv.Ascii:
+		return StripANSI(style.Render(str))
+	case termenv.TrueColor:
+		return style.Render(str)
+	}
+	return sgrSeq.ReplaceAllStringFunc(style.Render(str), func(seq string) string {
+		return convertSGR(p, seq)
+	})
+}
+
+// convertSGR converts the colors of the SGR sequence to the color profile,
+// the other parameters are kept as is
+func convertSGR(p termenv.Profile, seq string) string {
+	params := strings.Split(seq[2:len(seq)-1], ";")
+	out := make([]string, 0, len(params))
+	for i := 0; i < len(params); i++ {
+		n, err := strconv.Atoi(params[i])
+		if err != nil {
+			out = append(out, params[i])
+			continue
+		}
+
+		var c termenv.Color
+		bg := n >= 40 && n <= 49 || n >= 100 && n <= 107
+		switch {
+		case (n == 38 || n == 48) && i+2 < len(params) && params[i+1] == "5":
+			idx, _ := strconv.Atoi(params[i+2])
+			c = termenv.ANSI256Color(idx)
+			i += 2
+		case (n == 38 || n == 48) && i+4 < len(params) && params[i+1] == "2":
+			var rgb [3]int
+			for j := range rgb {
+				rgb[j], _ = strconv.Atoi(params[i+2+j])
+			}
+			c = termenv.RGBColor(fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2]))
+			i += 4
+		case n >= 30 && n <= 37, n >= 40 && n <= 47:
+			c = termenv.ANSIColor(n % 10)
+		case n >= 90 && n <= 97, n >= 100 && n <= 107:
+			c = termenv.ANSIColor(n%10 + 8)
+		default:
+			out = append(out, params[i])
+			continue
+		}
+		if c = p.Convert(c); c != nil {
+			if s := c.Sequence(bg); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	if len(out) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(out, ";") + "m"
+}
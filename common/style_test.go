@@ -0,0 +1,48 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestConvertSGR(t *testing.T) {
+	tests := []struct {
+		name string
+		p    termenv.Profile
+		seq  string
+		want string
+	}{
+		{"truecolor to 256", termenv.ANSI256, "\x1b[38;2;255;136;0m", "\x1b[38;5;208m"},
+		{"truecolor to 16", termenv.ANSI, "\x1b[38;2;255;136;0m", "\x1b[91m"},
+		{"truecolor background to 256", termenv.ANSI256, "\x1b[48;2;255;136;0m", "\x1b[48;5;208m"},
+		{"256 kept", termenv.ANSI256, "\x1b[38;5;212m", "\x1b[38;5;212m"},
+		{"256 to 16", termenv.ANSI, "\x1b[38;5;212m", "\x1b[95m"},
+		{"256 background to 16", termenv.ANSI, "\x1b[48;5;212m", "\x1b[105m"},
+		{"basic kept", termenv.ANSI, "\x1b[31m", "\x1b[31m"},
+		{"bright kept", termenv.ANSI, "\x1b[92m", "\x1b[92m"},
+		{"basic background kept", termenv.ANSI, "\x1b[44m", "\x1b[44m"},
+		{"bright background kept", termenv.ANSI256, "\x1b[101m", "\x1b[101m"},
+		{"attributes kept", termenv.ANSI, "\x1b[1;4;38;5;212;48;2;255;136;0m", "\x1b[1;4;95;101m"},
+		{"reset kept", termenv.ANSI, "\x1b[0m", "\x1b[0m"},
+		{"empty kept", termenv.ANSI, "\x1b[m", "\x1b[m"},
+		{"colors removed for Ascii", termenv.Ascii, "\x1b[38;2;255;136;0;48;5;212;31m", ""},
+		{"attributes kept for Ascii", termenv.Ascii, "\x1b[1;38;5;212;4m", "\x1b[1;4m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertSGR(tt.p, tt.seq); got != tt.want {
+				t.Errorf("convertSGR(%v, %q) = %q, want %q", tt.p, tt.seq, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderStyleAscii(t *testing.T) {
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212")).Padding(0, 1)
+	if got := RenderStyle(termenv.Ascii, style, "x"); got != " x " {
+		t.Errorf("RenderStyle() = %q, want the padding without escape sequences", got)
+	}
+}
@@ -0,0 +1,130 @@
+package common
+
+// Theme is the set of colors used by all components, the colors are
+// ANSI color numbers("2", "241") or hex colors("#B14FFF")
+type Theme struct {
+	// Header is the color of the selector header and the filter line
+	Header string
+	// Footer is the color of the selector footer
+	Footer string
+	// Cursor is the color of the selector cursor
+	Cursor string
+	// Selected is the color of the selected item
+	Selected string
+	// UnSelected is the color of the unselected items
+	UnSelected string
+	// Checked is the color of the checked mark in the multi-selection mode
+	Checked string
+	// Matched is the color of the runes matched by the filter
+	Matched string
+	// Finished is the color of the finished message
+	Finished string
+	// Prompt is the color of the default prompt
+	Prompt string
+	// Success is the color of the success marks and messages
+	Success string
+	// Error is the color of the error marks and messages
+	Error string
+	// Subtle is the color of the secondary information, e.g. the empty
+	// cells of the progress bar and the log lines
+	Subtle string
+	// HelpKey, HelpDesc and HelpSep are the colors of the help view
+	HelpKey  string
+	HelpDesc string
+	HelpSep  string
+	// GradientStart and GradientEnd are the hex colors of the progress bar gradient
+	GradientStart string
+	GradientEnd   string
+}
+
+var defaultTheme = DarkTheme()
+
+// DarkTheme returns the theme for the terminals with a dark background,
+// it is the default theme
+func DarkTheme() Theme {
+	return Theme{
+		Header:        "15",
+		Footer:        "15",
+		Cursor:        "2",
+		Selected:      "14",
+		UnSelected:    "8",
+		Checked:       "2",
+		Matched:       "11",
+		Finished:      "2",
+		Prompt:        "2",
+		Success:       "2",
+		Error:         "1",
+		Subtle:        "241",
+		HelpKey:       "246",
+		HelpDesc:      "241",
+		HelpSep:       "239",
+		GradientStart: "#B14FFF",
+		GradientEnd:   "#00FFA3",
+	}
+}
+
+// LightTheme returns the theme for the terminals with a light background
+func LightTheme() Theme {
+	return Theme{
+		Header:        "0",
+		Footer:        "0",
+		Cursor:        "28",
+		Selected:      "25",
+		UnSelected:    "244",
+		Checked:       "28",
+		Matched:       "166",
+		Finished:      "28",
+		Prompt:        "28",
+		Success:       "28",
+		Error:         "1",
+		Subtle:        "245",
+		HelpKey:       "240",
+		HelpDesc:      "245",
+		HelpSep:       "250",
+		GradientStart: "#7B2FBE",
+		GradientEnd:   "#00875F",
+	}
+}
+
+// HighContrastTheme returns the theme that only uses the bright basic
+// colors, it keeps the text readable on any background
+func HighContrastTheme() Theme {
+	return Theme{
+		Header:        "15",
+		Footer:        "15",
+		Cursor:        "11",
+		Selected:      "11",
+		UnSelected:    "15",
+		Checked:       "10",
+		Matched:       "14",
+		Finished:      "10",
+		Prompt:        "10",
+		Success:       "10",
+		Error:         "9",
+		Subtle:        "7",
+		HelpKey:       "15",
+		HelpDesc:      "7",
+		HelpSep:       "7",
+		GradientStart: "#FFFF00",
+		GradientEnd:   "#00FFFF",
+	}
+}
+
+// SetDefaultTheme sets the process-wide default theme, it is used by the
+// components without their own Theme, and should be called before they run
+func SetDefaultTheme(t Theme) {
+	defaultTheme = t
+}
+
+// DefaultTheme returns the process-wide default theme
+func DefaultTheme() Theme {
+	return defaultTheme
+}
+
+// ThemeOrDefault returns the given theme, or the default theme if it is nil
+func ThemeOrDefault(t *Theme) Theme {
+	if t == nil {
+		return defaultTheme
+	}
+	return *t
+}
@@ -2,10 +2,8 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mritd/bubbles/progressbar"
 )
 
@@ -13,7 +11,7 @@ func main() {
 	m := &progressbar.Model{
 		Width:       40,
 		InitMessage: "Initializing, please wait...",
-		Stages: []progressbar.ProgressFunc{
+		Steps: progressbar.FromFuncs(
 			func() (string, error) {
 				time.Sleep(time.Second)
 				return "🐌 INFO: stage1", nil
@@ -34,16 +32,11 @@ func main() {
 				time.Sleep(time.Second)
 				return "🐌🐌🐌🐌🐌 INFO: stage5", fmt.Errorf("🐞 Error: test error")
 			},
-		},
+		),
 	}
 
-	p := tea.NewProgram(m)
-	err := p.Start()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	if m.Error() != nil {
+	// prints one line per stage when stdout is not a terminal, e.g. in CI
+	if err := m.Run(); err != nil {
 		fmt.Printf("Stage func [%d] run failed: %s\n", m.Index()+1, m.Error())
 	}
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mritd/bubbles/progressbar"
+)
+
+// deploy returns the stages of a fake deployment, each step takes the given time
+func deploy(step time.Duration, fail bool) []progressbar.Stage {
+	var stages []progressbar.Stage
+	for i := 1; i <= 5; i++ {
+		i := i
+		stages = append(stages, progressbar.Stage{
+			Name: fmt.Sprintf("step %d", i),
+			Func: func(ctx context.Context, r *progressbar.Reporter) (string, error) {
+				select {
+				case <-ctx.Done():
+					return "", ctx.Err()
+				case <-time.After(step):
+				}
+				if fail && i == 4 {
+					return "", fmt.Errorf("🐞 step %d failed", i)
+				}
+				return fmt.Sprintf("step %d done", i), nil
+			},
+		})
+	}
+	return stages
+}
+
+func main() {
+	g := &progressbar.Group{Width: 30, Policy: progressbar.WaitAll}
+	g.Add("cluster-a", &progressbar.Model{Steps: deploy(300*time.Millisecond, false)})
+	g.Add("cluster-b", &progressbar.Model{Steps: deploy(500*time.Millisecond, false)})
+	g.Add("cluster-c", &progressbar.Model{Steps: deploy(400*time.Millisecond, true)})
+
+	if err := g.Run(); err != nil {
+		log.Println(err)
+	}
+	for label, err := range g.Errors() {
+		fmt.Printf("%s failed: %s\n", label, err)
+	}
+}
@@ -1,11 +1,13 @@
 module github.com/mritd/bubbles
 
-go 1.17
+go 1.18
 
 require (
 	github.com/charmbracelet/bubbles v0.8.0
 	github.com/charmbracelet/bubbletea v0.14.1
+	github.com/charmbracelet/lipgloss v0.1.2
 	github.com/lucasb-eyer/go-colorful v1.2.0
+	github.com/mattn/go-isatty v0.0.13
 	github.com/mattn/go-runewidth v0.0.13
 	github.com/muesli/reflow v0.3.0
 	github.com/muesli/termenv v0.9.0
@@ -13,9 +15,7 @@ require (
 
 require (
 	github.com/atotto/clipboard v0.1.2 // indirect
-	github.com/charmbracelet/lipgloss v0.1.2 // indirect
 	github.com/containerd/console v1.0.1 // indirect
-	github.com/mattn/go-isatty v0.0.13 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
 	golang.org/x/sys v0.0.0-20210119212857-b64e53b001e4 // indirect
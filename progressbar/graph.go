@@ -0,0 +1,143 @@
+package progressbar
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CycleError is the error of the stages whose dependencies form a cycle,
+// it is detected when the progress bar initializes
+type CycleError struct {
+	// Names are the names of the stages in the cycle, the first stage is repeated at the end
+	Names []string
+}
+
+// Error implements the error interface
+func (e *CycleError) Error() string {
+	return "dependency cycle: " + strings.Join(e.Names, " -> ")
+}
+
+// SkipError is the reason why a stage is skipped, the stage depends
+// directly or indirectly on a failed stage
+type SkipError struct {
+	// Index is the index of the skipped stage
+	Index int
+	// Name is the name of the skipped stage
+	Name string
+	// Dependency is the name of the failed stage
+	Dependency string
+}
+
+// Error implements the error interface
+func (e *SkipError) Error() string {
+	name := e.Name
+	if name == "" {
+		name = fmt.Sprintf("stage %d", e.Index+1)
+	}
+	return fmt.Sprintf("%s skipped: dependency %s failed", name, e.Dependency)
+}
+
+// buildGraph resolves the dependencies of the stages by name, and checks
+// that the dependencies exist and do not form a cycle
+func (m *Model) buildGraph() error {
+	names := make(map[string]int)
+	for i, stage := range m.stages {
+		if _, ok := names[stage.Name]; ok && stage.Name != "" {
+			// a duplicate name is only an error if it is used as a dependency
+			names[stage.Name] = -1
+			continue
+		}
+		names[stage.Name] = i
+	}
+
+	m.deps = make([][]int, len(m.stages))
+	m.dependents = make([][]int, len(m.stages))
+	for i, stage := range m.stages {
+		for _, dep := range stage.DependsOn {
+			j, ok := names[dep]
+			switch {
+			case !ok || dep == "":
+				return fmt.Errorf("%s depends on unknown stage %q", m.stageName(i), dep)
+			case j < 0:
+				return fmt.Errorf("%s depends on %q, but the stage name is not unique", m.stageName(i), dep)
+			}
+			m.deps[i] = append(m.deps[i], j)
+			m.dependents[j] = append(m.dependents[j], i)
+		}
+	}
+
+	// depth-first search, a stage visited again while it is on the path closes a cycle
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(m.stages))
+	var path []int
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			var cycle []string
+			for k := len(path) - 1; k >= 0; k-- {
+				if path[k] == i {
+					for _, j := range path[k:] {
+						cycle = append(cycle, m.stageName(j))
+					}
+					break
+				}
+			}
+			return &CycleError{Names: append(cycle, m.stageName(i))}
+		case visited:
+			return nil
+		}
+		state[i] = visiting
+		path = append(path, i)
+		for _, j := range m.deps[i] {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		return nil
+	}
+	for i := range m.stages {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ready determine whether the stage can be started, all its
+// dependencies must have succeeded
+func (m *Model) ready(index int) bool {
+	if m.started[index] || m.skipped[index] != nil {
+		return false
+	}
+	for _, dep := range m.deps[index] {
+		if !m.succeeded[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// skipDependents skips the stages depending directly or indirectly
+// on the failed stage, it returns the number of the skipped stages
+func (m *Model) skipDependents(failed int) int {
+	var n int
+	queue := append([]int(nil), m.dependents[failed]...)
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		if m.started[i] || m.skipped[i] != nil {
+			continue
+		}
+		m.skipped[i] = &SkipError{Index: i, Name: m.stages[i].Name, Dependency: m.stageName(failed)}
+		n++
+		queue = append(queue, m.dependents[i]...)
+	}
+	return n
+}
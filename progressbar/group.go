@@ -0,0 +1,292 @@
+package progressbar
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+	"github.com/mritd/bubbles/common"
+	"github.com/muesli/reflow/indent"
+	"github.com/muesli/termenv"
+)
+
+// FailurePolicy decides how the Group reacts when a progress bar fails
+type FailurePolicy int
+
+const (
+	// WaitAll keeps the other progress bars running, the Group exits after all of them finish
+	WaitAll FailurePolicy = iota
+	// FailFast stops all progress bars and exits once any of them fails
+	FailFast
+)
+
+// AddMsg adds a progress bar to the running Group, it can be returned from a tea.Cmd
+type AddMsg struct {
+	Label string
+	Model *Model
+}
+
+// RemoveMsg removes a progress bar from the running Group, it can be returned from a tea.Cmd
+type RemoveMsg struct {
+	Label string
+}
+
+// groupBar is a labeled progress bar hosted by the Group
+type groupBar struct {
+	label    string
+	model    *Model
+	finished bool
+	// stopped indicates that the progress bar was stopped by the group
+	// before it finished, e.g. by the FailFast policy
+	stopped bool
+}
+
+// Group hosts multiple progress bars in one program, the bars are rendered
+// stacked with aligned labels, and they can be added or removed while running;
+// the program quits once all bars have finished or been removed, a Group
+// without any bar quits immediately.
+//
+// The ui rendering success style is as follows:
+//
+//	cluster-a  ████████████████░░░░  80%  deploying...
+//	cluster-b  ████████████████████ 100%  done
+type Group struct {
+	// Width is the width of all hosted progress bars, if 0 or less the
+	// width of each progress bar is kept
+	Width int
+	// Policy decides how the Group reacts when a progress bar fails
+	Policy FailurePolicy
+	// KeyMap the key bindings of the group, defaults to DefaultKeyMap()
+	KeyMap *KeyMap
+	// ShowHelp displays the help view of the key bindings under the bars
+	ShowHelp bool
+	// Theme the colors of the group, it is also used by the hosted progress
+	// bars without their own Theme, defaults to common.DefaultTheme()
+	Theme *common.Theme
+	// ColorProfile overrides the color profile of the group, it is also used by
+	// the hosted progress bars without their own ColorProfile
+	ColorProfile *termenv.Profile
+
+	bars     []*groupBar
+	init     bool
+	canceled bool
+	failed   bool
+	help     common.Help
+}
+
+// Add adds a labeled progress bar to the group, the returned command starts
+// the progress bar if the group is already running
+func (g *Group) Add(label string, m *Model) tea.Cmd {
+	m.hosted = true
+	if g.Width > 0 {
+		m.Width = g.Width
+	}
+	if m.Theme == nil {
+		m.Theme = g.Theme
+	}
+	if m.ColorProfile == nil {
+		m.ColorProfile = g.ColorProfile
+	}
+	g.bars = append(g.bars, &groupBar{label: label, model: m})
+	if !g.init {
+		return nil
+	}
+	return m.Init()
+}
+
+// Remove removes the progress bar with the given label from the group,
+// its running stages are cancelled
+func (g *Group) Remove(label string) {
+	for i, b := range g.bars {
+		if b.label == label {
+			b.model.stop()
+			g.bars = append(g.bars[:i], g.bars[i+1:]...)
+			return
+		}
+	}
+}
+
+// Bar returns the progress bar with the given label, nil if not found
+func (g *Group) Bar(label string) *Model {
+	for _, b := range g.bars {
+		if b.label == label {
+			return b.model
+		}
+	}
+	return nil
+}
+
+// Init starts all progress bars added before the program runs
+func (g *Group) Init() tea.Cmd {
+	g.initData()
+	if len(g.bars) == 0 {
+		return tea.Quit
+	}
+	cmds := make([]tea.Cmd, 0, len(g.bars))
+	for _, b := range g.bars {
+		cmds = append(cmds, b.model.Init())
+	}
+	return tea.Batch(cmds...)
+}
+
+// initData initialize the data model, set the default value and
+// fix the wrong parameter settings during initialization
+func (g *Group) initData() {
+	if g.KeyMap == nil {
+		km := DefaultKeyMap()
+		g.KeyMap = &km
+	}
+	g.help.Theme = g.Theme
+	g.help.Profile = g.ColorProfile
+	g.init = true
+}
+
+// Update method responds to various events and modifies the data model
+// according to the corresponding events
+func (g *Group) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !g.init {
+		return g, g.Init()
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if common.Matches(msg, g.KeyMap.Cancel) {
+			g.canceled = true
+			g.stopAll()
+			return g, tea.Quit
+		}
+		if common.Matches(msg, g.KeyMap.Help) {
+			g.help.ShowAll = !g.help.ShowAll
+		}
+		return g, nil
+	case AddMsg:
+		return g, g.Add(msg.Label, msg.Model)
+	case RemoveMsg:
+		g.Remove(msg.Label)
+		return g, g.quitIfFinished()
+	case finishedMsg:
+		for _, b := range g.bars {
+			if b.model.id != msg.id {
+				continue
+			}
+			b.finished = true
+			// the error of a stopped progress bar is caused by the group
+			if b.model.Error() != nil && !b.stopped {
+				g.failed = true
+				if g.Policy == FailFast {
+					g.stopAll()
+					return g, tea.Quit
+				}
+			}
+		}
+		return g, g.quitIfFinished()
+	}
+
+	// the messages of the progress bars are dispatched by their ids
+	var cmds []tea.Cmd
+	for _, b := range g.bars {
+		_, cmd := b.model.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return g, tea.Batch(cmds...)
+}
+
+// quitIfFinished returns tea.Quit if all progress bars have finished,
+// or the last one has been removed
+func (g *Group) quitIfFinished() tea.Cmd {
+	for _, b := range g.bars {
+		if !b.finished {
+			return nil
+		}
+	}
+	return tea.Quit
+}
+
+// stopAll cancels the running stages of the unfinished progress bars and
+// marks them as stopped; it does not touch the state of the models, which
+// are still running in their own goroutines in the plain-text mode
+func (g *Group) stopAll() {
+	for _, b := range g.bars {
+		if !b.finished {
+			b.stopped = true
+			b.model.stop()
+		}
+	}
+}
+
+// View reads the data state of the data model for rendering
+func (g Group) View() string {
+	var labelWidth int
+	for _, b := range g.bars {
+		if w := runewidth.StringWidth(b.label); w > labelWidth {
+			labelWidth = w
+		}
+	}
+
+	var view string
+	for _, b := range g.bars {
+		line := runewidth.FillRight(b.label, labelWidth) + "  " + b.model.BarView()
+		if b.stopped {
+			line += "  " + b.model.subtle("stopped")
+		} else if b.model.Error() != nil {
+			line += "  " + b.model.makeError(b.model.Error().Error())
+		} else if b.model.message != "" {
+			line += "  " + b.model.makeInfo(b.model.message)
+		}
+		view += line + "\n"
+	}
+	if g.ShowHelp {
+		view += "\n" + g.HelpView() + "\n"
+	}
+	return indent.String("\n"+view+"\n", 2)
+}
+
+// HelpView renders the help view of the active key bindings
+func (g Group) HelpView() string {
+	if g.KeyMap == nil {
+		return ""
+	}
+	return g.help.View(g)
+}
+
+// ShortHelp return the bindings displayed in the short help view
+func (g Group) ShortHelp() []common.Binding {
+	return []common.Binding{g.KeyMap.Cancel, g.KeyMap.Help}
+}
+
+// FullHelp return the bindings displayed in the expanded help view
+func (g Group) FullHelp() [][]common.Binding {
+	return [][]common.Binding{{g.KeyMap.Cancel, g.KeyMap.Help}}
+}
+
+// Canceled determine whether the operation is cancelled
+func (g *Group) Canceled() bool {
+	return g.canceled
+}
+
+// Failed determine whether any progress bar failed
+func (g *Group) Failed() bool {
+	return g.failed
+}
+
+// Errors returns the errors of the failed progress bars, keyed by the label;
+// the progress bars stopped by the group are reported by Stopped instead
+func (g *Group) Errors() map[string]error {
+	errs := make(map[string]error)
+	for _, b := range g.bars {
+		if err := b.model.Error(); err != nil && !b.stopped {
+			errs[b.label] = err
+		}
+	}
+	return errs
+}
+
+// Stopped returns the labels of the progress bars stopped by the group before
+// they finished, e.g. by the FailFast policy or the Cancel key
+func (g *Group) Stopped() []string {
+	var labels []string
+	for _, b := range g.bars {
+		if b.stopped {
+			labels = append(labels, b.label)
+		}
+	}
+	return labels
+}
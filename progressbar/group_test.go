@@ -0,0 +1,48 @@
+package progressbar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGroupFailFastStopped(t *testing.T) {
+	g := &Group{Policy: FailFast}
+	g.Add("x", &Model{Steps: []Stage{{Name: "wait", Func: func(ctx context.Context, _ *Reporter) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}}}})
+	g.Add("y", &Model{Steps: []Stage{{Name: "fail", Func: func(context.Context, *Reporter) (string, error) {
+		return "", errors.New("boom")
+	}}}})
+
+	var out bytes.Buffer
+	if err := g.RunPlain(&out); err == nil || err.Error() != "1 progress bars failed" {
+		t.Errorf("RunPlain() = %v, want 1 failed progress bar", err)
+	}
+	errs := g.Errors()
+	if len(errs) != 1 || errs["y"] == nil {
+		t.Errorf("Errors() = %v, want only y", errs)
+	}
+	if stopped := g.Stopped(); !reflect.DeepEqual(stopped, []string{"x"}) {
+		t.Errorf("Stopped() = %v, want [x]", stopped)
+	}
+}
+
+func TestGroupQuitWithoutBars(t *testing.T) {
+	g := &Group{}
+	if cmd := g.Init(); cmd == nil || cmd() != tea.Quit() {
+		t.Error("the group without bars does not quit on Init")
+	}
+
+	g = &Group{}
+	g.Add("x", &Model{Steps: FromFuncs(func() (string, error) { return "", nil })})
+	g.Init()
+	if _, cmd := g.Update(RemoveMsg{Label: "x"}); cmd == nil || cmd() != tea.Quit() {
+		t.Error("the group does not quit after the last bar is removed")
+	}
+}
@@ -0,0 +1,78 @@
+package progressbar
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Reader wraps an io.Reader and reports the bytes read to a Reporter
+type Reader struct {
+	r        io.Reader
+	reporter *Reporter
+}
+
+// NewReader returns a Reader that reports the bytes read from r, total is
+// the size of r; if it is unknown(less than or equal to 0), the stage is
+// displayed as indeterminate
+func NewReader(r io.Reader, reporter *Reporter, total int64) *Reader {
+	reporter.SetTotalBytes(total)
+	return &Reader{r: r, reporter: reporter}
+}
+
+// Read implements the io.Reader interface
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.reporter.AddBytes(int64(n))
+	return n, err
+}
+
+// Writer wraps an io.Writer and reports the bytes written to a Reporter
+type Writer struct {
+	w        io.Writer
+	reporter *Reporter
+}
+
+// NewWriter returns a Writer that reports the bytes written to w, total is
+// the number of bytes expected to be written; if it is unknown(less than or
+// equal to 0), the stage is displayed as indeterminate
+func NewWriter(w io.Writer, reporter *Reporter, total int64) *Writer {
+	reporter.SetTotalBytes(total)
+	return &Writer{w: w, reporter: reporter}
+}
+
+// Write implements the io.Writer interface
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.reporter.AddBytes(int64(n))
+	return n, err
+}
+
+// CopyStage returns a stage that copies src to dst and updates the progress
+// continuously, total is the size of src, less than or equal to 0 if unknown
+func CopyStage(name string, dst io.Writer, src io.Reader, total int64) Stage {
+	return Stage{
+		Name: name,
+		Func: func(ctx context.Context, r *Reporter) (string, error) {
+			n, err := io.Copy(dst, NewReader(&contextReader{ctx: ctx, r: src}, r, total))
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s: %s copied", name, formatBytes(float64(n))), nil
+		},
+	}
+}
+
+// contextReader stops reading once the context is cancelled
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read implements the io.Reader interface
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,56 @@
+package progressbar
+
+import (
+	"github.com/mritd/bubbles/common"
+)
+
+// KeyMap defines the key bindings of the progress bar
+type KeyMap struct {
+	// Cancel cancels the execution and exits
+	Cancel common.Binding
+	// Help switches between the short and the expanded help view
+	Help common.Binding
+}
+
+// DefaultKeyMap return the default key bindings of the progress bar
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Cancel: common.NewBinding(common.WithKeys("q", "esc", "ctrl+c"), common.WithHelp("q", "quit")),
+		Help:   common.NewBinding(common.WithKeys("?"), common.WithHelp("?", "toggle help")),
+	}
+}
+
+// VimKeyMap return the key bindings following the vim conventions
+func VimKeyMap() KeyMap {
+	return KeyMap{
+		Cancel: common.NewBinding(common.WithKeys("q", "ctrl+c"), common.WithHelp("q", "quit")),
+		Help:   common.NewBinding(common.WithKeys("?"), common.WithHelp("?", "toggle help")),
+	}
+}
+
+// EmacsKeyMap return the key bindings following the emacs conventions
+func EmacsKeyMap() KeyMap {
+	return KeyMap{
+		Cancel: common.NewBinding(common.WithKeys("ctrl+g", "ctrl+c"), common.WithHelp("C-g", "quit")),
+		Help:   common.NewBinding(common.WithKeys("?"), common.WithHelp("?", "toggle help")),
+	}
+}
+
+// ShortHelp return the bindings displayed in the short help view
+func (m Model) ShortHelp() []common.Binding {
+	return []common.Binding{m.KeyMap.Cancel, m.KeyMap.Help}
+}
+
+// FullHelp return the bindings displayed in the expanded help view
+func (m Model) FullHelp() [][]common.Binding {
+	return [][]common.Binding{{m.KeyMap.Cancel, m.KeyMap.Help}}
+}
+
+// HelpView renders the help view of the active key bindings, "?" switches
+// between the short and the expanded view
+func (m Model) HelpView() string {
+	if m.KeyMap == nil {
+		return ""
+	}
+	return m.help.View(m)
+}
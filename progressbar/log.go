@@ -0,0 +1,119 @@
+package progressbar
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// logBuffer retains the log lines written by the stages, it is shared
+// by all reporters of a progress bar and safe for concurrent use
+type logBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	// onAppend is called with the appended lines, it is used to print
+	// the log lines immediately in the plain-text mode
+	onAppend func(lines ...string)
+}
+
+// append appends the lines to the buffer
+func (b *logBuffer) append(lines ...string) {
+	b.mu.Lock()
+	b.lines = append(b.lines, lines...)
+	onAppend := b.onAppend
+	b.mu.Unlock()
+
+	if onAppend != nil {
+		onAppend(lines...)
+	}
+}
+
+// all returns a copy of all lines
+func (b *logBuffer) all() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.lines...)
+}
+
+// tail returns a copy of the last n lines
+func (b *logBuffer) tail(n int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n > len(b.lines) {
+		n = len(b.lines)
+	}
+	return append([]string(nil), b.lines[len(b.lines)-n:]...)
+}
+
+// Write implements io.Writer, the written data is split into log lines,
+// an incomplete line is kept until it is terminated or the stage finishes
+func (r *Reporter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	r.partial = append(r.partial, p...)
+	var lines []string
+	for {
+		i := bytes.IndexByte(r.partial, '\n')
+		if i < 0 {
+			break
+		}
+		lines = append(lines, r.logPrefix+strings.TrimRight(string(r.partial[:i]), "\r"))
+		r.partial = r.partial[i+1:]
+	}
+	r.mu.Unlock()
+
+	if r.log != nil && len(lines) > 0 {
+		r.log.append(lines...)
+	}
+	return len(p), nil
+}
+
+// Logf writes a formatted line to the log of the progress bar
+func (r *Reporter) Logf(format string, a ...interface{}) {
+	_, _ = fmt.Fprintf(r, strings.TrimSuffix(format, "\n")+"\n", a...)
+}
+
+// flushLog writes the incomplete line to the log
+func (r *Reporter) flushLog() {
+	r.mu.Lock()
+	partial := r.partial
+	r.partial = nil
+	r.mu.Unlock()
+
+	if r.log != nil && len(partial) > 0 {
+		r.log.append(r.logPrefix + strings.TrimRight(string(partial), "\r"))
+	}
+}
+
+// Logs returns all log lines written by the stages, the lines of the
+// concurrently running stages are prefixed with the stage name
+func (m Model) Logs() []string {
+	if m.logs == nil {
+		return nil
+	}
+	return m.logs.all()
+}
+
+// WriteLogs writes all log lines to w, e.g. to keep the log in a file
+// after a failed run
+func (m Model) WriteLogs(w io.Writer) error {
+	for _, line := range m.Logs() {
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logView renders the last LogHeight lines of the log
+func (m Model) logView() string {
+	if m.LogHeight <= 0 || m.logs == nil {
+		return ""
+	}
+	lines := m.logs.tail(m.LogHeight)
+	for i := range lines {
+		lines[i] = m.subtle("│ " + lines[i])
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,194 @@
+package progressbar
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mritd/bubbles/common"
+)
+
+// plainTimeFormat is the timestamp format of the lines printed in the plain-text mode
+const plainTimeFormat = "2006-01-02 15:04:05"
+
+// Run executes the stages: in a bubbletea program on a terminal, or by RunPlain
+// with stdout in the plain-text mode(see common.IsPlain); it returns the error
+// of the program, or Error() after the execution ends
+func (m *Model) Run() error {
+	if common.IsPlain() {
+		return m.RunPlain(os.Stdout)
+	}
+	if err := tea.NewProgram(m).Start(); err != nil {
+		return err
+	}
+	return m.Error()
+}
+
+// RunPlain executes the stages without the TUI, it is intended for the
+// plain-text mode(see common.IsPlain): one line with a timestamp is written
+// to out for each finished stage, the log lines of the stages are written as
+// they arrive, and no ANSI escape sequence is emitted. It returns Error()
+func (m *Model) RunPlain(out io.Writer) error {
+	if !m.init {
+		m.initData()
+	}
+
+	var mu sync.Mutex
+	printf := func(format string, a ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintf(out, "%s %s\n", time.Now().Format(plainTimeFormat), common.StripANSI(fmt.Sprintf(format, a...)))
+	}
+
+	if m.err != nil {
+		m.endTime = time.Now()
+		printf("ERROR: %s", m.err)
+		return m.err
+	}
+	if len(m.stages) == 0 {
+		m.loaded = true
+		return nil
+	}
+	m.logs.onAppend = func(lines ...string) {
+		for _, line := range lines {
+			printf("  | %s", line)
+		}
+	}
+
+	results := make(chan stageMsg)
+	start := func() {
+		for _, i := range m.startReady() {
+			cmd := m.runStage(i)
+			go func() {
+				results <- cmd().(stageMsg)
+			}()
+		}
+	}
+
+	m.startTime = time.Now()
+	start()
+	reported := make(map[int]bool)
+	for msg := range results {
+		ended := m.finishStage(msg)
+		printf("%s", m.plainStageLine(msg.index, msg.message))
+		reported[msg.index] = true
+		for i := range m.stages {
+			if m.skipped[i] != nil && !reported[i] {
+				printf("%s", m.plainStageLine(i, ""))
+				reported[i] = true
+			}
+		}
+		if ended {
+			break
+		}
+		if m.err == nil {
+			start()
+		}
+	}
+
+	if summary := m.summaryView(); summary != "" {
+		for _, line := range strings.Split(summary, "\n") {
+			printf("%s", line)
+		}
+	}
+	return m.err
+}
+
+// plainStageLine renders the result of the finished stage as a line of the plain-text mode
+func (m *Model) plainStageLine(index int, message string) string {
+	line := fmt.Sprintf("[%d/%d] %s", index+1, len(m.stages), m.stageName(index))
+	if err := m.skipped[index]; err != nil {
+		return line + " skipped: dependency " + err.(*SkipError).Dependency + " failed"
+	}
+
+	var retries string
+	if n := m.retries[index]; n > 0 {
+		retries = fmt.Sprintf(" after %d retries", n)
+	}
+	if err := m.errs[index]; err != nil {
+		if m.stages[index].Optional {
+			line += " (optional)"
+		}
+		return fmt.Sprintf("%s failed in %s%s: %s", line, formatDuration(m.durations[index]), retries, err)
+	}
+	line = fmt.Sprintf("%s succeeded in %s%s", line, formatDuration(m.durations[index]), retries)
+	if message != "" {
+		line += ": " + message
+	}
+	return line
+}
+
+// Run executes the progress bars of the group, see Model.Run; it returns
+// an error if the program fails or any progress bar fails
+func (g *Group) Run() error {
+	if common.IsPlain() {
+		return g.RunPlain(os.Stdout)
+	}
+	if err := tea.NewProgram(g).Start(); err != nil {
+		return err
+	}
+	return g.failure()
+}
+
+// failure returns the error reporting the failed progress bars, nil if none failed
+func (g *Group) failure() error {
+	if !g.failed {
+		return nil
+	}
+	return fmt.Errorf("%d progress bars failed", len(g.Errors()))
+}
+
+// RunPlain executes the progress bars of the group without the TUI, see
+// Model.RunPlain; the lines of each progress bar are prefixed with its label
+func (g *Group) RunPlain(out io.Writer) error {
+	g.initData()
+	// the progress bars are initialized before running, so that
+	// they can be stopped safely by the FailFast policy
+	for _, b := range g.bars {
+		b.model.initData()
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, b := range g.bars {
+		wg.Add(1)
+		go func(b *groupBar) {
+			defer wg.Done()
+			err := b.model.RunPlain(&labelWriter{label: "[" + b.label + "] ", out: out, mu: &mu})
+			mu.Lock()
+			defer mu.Unlock()
+			b.finished = true
+			// the error of a stopped progress bar is caused by the group
+			if err != nil && !b.stopped {
+				g.failed = true
+				if g.Policy == FailFast {
+					g.stopAll()
+				}
+			}
+		}(b)
+	}
+	wg.Wait()
+	return g.failure()
+}
+
+// labelWriter prefixes the lines written by a progress bar of the group
+// with its label, the writers of the group share the same lock
+type labelWriter struct {
+	label string
+	out   io.Writer
+	mu    *sync.Mutex
+}
+
+// Write implements io.Writer, each write of Model.RunPlain is a complete line
+func (w *labelWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := io.WriteString(w.out, w.label+string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -1,13 +1,19 @@
 package progressbar
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lucasb-eyer/go-colorful"
+	"github.com/mritd/bubbles/common"
 	"github.com/muesli/reflow/indent"
 	"github.com/muesli/termenv"
 )
@@ -17,49 +23,211 @@ const (
 	progressEmptyChar = "░"
 )
 
-// General stuff for styling the view
-var (
-	term          = termenv.ColorProfile()
-	subtle        = makeFgStyle("241")
-	progressEmpty = subtle(progressEmptyChar)
-)
-
 // ProgressFunc is a simple function, the progress bar will step a certain distance after each execution
 type ProgressFunc func() (string, error)
 
+// DefaultBackoff is the delay before the first retry of a failed stage,
+// it doubles after each retry
+const DefaultBackoff = time.Second
+
+// tickInterval is the refresh interval of the partial progress of the running stages
+const tickInterval = 100 * time.Millisecond
+
+// spinnerFrames are the animation frames of the indeterminate stages
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// lastID is the last id assigned to a progress bar, the messages of the progress
+// bars carry the id so that multiple progress bars can run in one program
+var lastID int64
+
+// nextID returns the next progress bar id
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// stageMsg is sent to the Update method when a stage finishes
+type stageMsg struct {
+	id      int
+	index   int
+	message string
+	err     error
+	retries int
+}
+
+// tickMsg is sent periodically to refresh the partial progress of the running stages
+type tickMsg struct {
+	id int
+}
+
+// finishedMsg is sent instead of tea.Quit when the progress bar is hosted by a Group
+type finishedMsg struct {
+	id int
+}
+
 // Model is a data container used to store TUI status information.
 type Model struct {
-	Width       int
-	Stages      []ProgressFunc
+	// Width is the width of the progress bar, defaults to 40
+	Width int
+	// Stages are the simple stages, each of them has the same weight
+	//
+	// Deprecated: use Steps with FromFuncs instead, Stages is ignored if Steps is set
+	Stages []ProgressFunc
+	// Steps are the stages of the progress bar, they are weighted and can report
+	// the partial progress while running; FromFuncs converts the simple ProgressFuncs
+	Steps []Stage
+	// InitMessage is displayed before any stage finishes
 	InitMessage string
-	stageIndex  int
-	message     string
-	err         error
-	progress    float64
-	loaded      bool
-	init        bool
-	canceled    bool
-}
-
-// Init performs some io initialization actions, The current Init returns the first ProgressFunc
-// to trigger the program to run.
-func (m Model) Init() tea.Cmd {
-	return func() tea.Msg {
-		if len(m.Stages) > 0 {
-			return m.Stages[m.stageIndex]
-		} else {
-			return nil
-		}
+	// Concurrency is the maximum number of stages running at the same time, less
+	// than 0 means no limit and 1 runs the stages one after another; 0 is the default,
+	// it runs the stages one after another unless any stage declares DependsOn, then
+	// the stages of the dependency graph run with maximal parallelism
+	Concurrency int
+	// Timeout is the maximum execution time of all stages, 0 means no limit
+	Timeout time.Duration
+	// KeyMap the key bindings of the progress bar, defaults to DefaultKeyMap()
+	KeyMap *KeyMap
+	// Indeterminate starts all stages in the indeterminate state, the bar bounces
+	// while an indeterminate stage is running, and switches to the determinate bar
+	// once the stage reports its progress(Reporter.Report or Reporter.SetTotalBytes)
+	Indeterminate bool
+	// ShowHelp displays the help view of the key bindings under the bar
+	ShowHelp bool
+	// ShowElapsed displays the elapsed time under the bar
+	ShowElapsed bool
+	// ShowETA displays the estimated remaining time under the bar
+	ShowETA bool
+	// ShowDurations lists the execution time of the finished stages
+	ShowDurations bool
+	// ShowThroughput displays the byte throughput reported by the stages
+	ShowThroughput bool
+	// ShowSummary displays the summary of the stages after the execution ends
+	ShowSummary bool
+	// Theme the colors of the progress bar, defaults to common.DefaultTheme()
+	Theme *common.Theme
+	// ColorProfile overrides the color profile of the progress bar, defaults to
+	// common.ColorProfile(); the gradient degrades to a solid color on termenv.ANSI
+	// and to the plain characters on termenv.Ascii
+	ColorProfile *termenv.Profile
+	// LogHeight is the number of the last log lines displayed under the bar,
+	// 0 hides the log region, the full log is available through Logs()
+	LogHeight int
+	// RenderFunc replaces the default rendering, the state of the progress bar
+	// can be read through the accessors(Progress, Elapsed, ETA, Throughput...)
+	RenderFunc func(m Model) string
+
+	stageIndex int
+	message    string
+	err        error
+	errs       map[int]error
+	progress   float64
+	loaded     bool
+	init       bool
+	canceled   bool
+	help       common.Help
+	// started is the indexes of the stages that have been started
+	started map[int]bool
+	// skipped is the reasons of the stages skipped because a dependency failed
+	skipped map[int]error
+	// deps and dependents are the dependency graph of the stages, resolved by name
+	deps       [][]int
+	dependents [][]int
+	// running is the indexes of the stages being executed
+	running map[int]bool
+	// done is the number of the finished stages
+	done int
+	// stages are the stages to be executed, it is a copy of Steps or the converted Stages
+	stages []Stage
+	// reporters are the progress reporters of the stages
+	reporters []*Reporter
+	// logs retains the log lines written by the stages
+	logs *logBuffer
+	// succeeded is the indexes of the stages finished without error
+	succeeded map[int]bool
+	// totalWeight is the sum of the weights of all stages
+	totalWeight float64
+	// ctx is the parent context of the stages, it is cancelled when the user quits
+	ctx    context.Context
+	cancel context.CancelFunc
+	// startTime is the time when the first stage starts
+	startTime time.Time
+	// endTime is the time when the execution ends
+	endTime time.Time
+	// stageStart is the start time of the stages
+	stageStart map[int]time.Time
+	// durations is the execution time of the finished stages
+	durations map[int]time.Duration
+	// retries is the retry count of the finished stages
+	retries map[int]int
+	// frame is the animation frame of the indeterminate bar, it increases on each tick
+	frame int
+	// id identifies the messages of the progress bar
+	id int
+	// hosted indicates that the progress bar is hosted by a Group, it does
+	// not quit the program when finished
+	hosted bool
+}
+
+// Init performs some io initialization actions, The current Init starts the
+// first stages to trigger the program to run.
+func (m *Model) Init() tea.Cmd {
+	m.initData()
+	// the invalid dependencies are reported before any stage starts
+	if m.err != nil {
+		m.endTime = time.Now()
+		return m.quit()
+	}
+	if len(m.stages) == 0 {
+		m.loaded = true
+		return m.quit()
 	}
+	m.startTime = time.Now()
+	return tea.Batch(m.dispatch(), m.tick())
 }
 
 // View reads the data state of the data model for rendering
 func (m Model) View() string {
-	prompt := indent.String("\n"+makeInfo(m.message), 2)
+	if m.RenderFunc != nil {
+		return m.RenderFunc(m)
+	}
+
+	prompt := indent.String("\n"+m.makeInfo(m.message), 2)
 	if m.err != nil {
-		prompt = indent.String("\n"+makeError(m.err.Error()), 2)
+		prompt = indent.String("\n"+m.makeError(m.err.Error()), 2)
+	}
+	bar := indent.String("\n"+m.BarView()+"\n\n", 2)
+	if stats := m.statsView(); stats != "" {
+		bar += indent.String(stats+"\n\n", 2)
+	}
+	// the stages being executed are listed under the bar when running concurrently,
+	// or when they report their progress
+	var running string
+	for i := range m.stages {
+		if !m.running[i] {
+			continue
+		}
+		view, active := m.reporters[i].runningView()
+		if !active && m.concurrency() == 1 {
+			continue
+		}
+		// the indeterminate stages are marked with an animated spinner
+		mark := "»"
+		if m.reporters[i].Indeterminate() {
+			mark = spinnerFrames[m.frame%len(spinnerFrames)]
+		}
+		running += m.subtle(strings.TrimRight(fmt.Sprintf("%s %s running... %s", mark, m.stageName(i), view), " ")) + "\n"
+	}
+	if running != "" {
+		bar += indent.String(running+"\n", 2)
+	}
+	if logs := m.logView(); logs != "" {
+		bar += indent.String(logs+"\n\n", 2)
+	}
+	if summary := m.summaryView(); summary != "" {
+		bar += indent.String(summary+"\n\n", 2)
+	}
+	if m.ShowHelp {
+		bar += indent.String(m.HelpView()+"\n\n", 2)
 	}
-	bar := indent.String("\n"+progressbar(m.Width, m.progress)+"%"+"\n\n", 2)
 	return prompt + bar
 }
 
@@ -71,102 +239,415 @@ func (m *Model) Canceled() bool {
 // Update method responds to various events and modifies the data model
 // according to the corresponding events
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// the model is embedded in another model which does not call Init
 	if !m.init {
-		m.initData()
-		m.message = makeInfo(m.InitMessage)
-		return m, nil
+		return m, m.Init()
 	}
 
-	// Make sure these keys always quit
-	if msg, ok := msg.(tea.KeyMsg); ok {
-		k := msg.String()
-		if k == "q" || k == "esc" || k == "ctrl+c" {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// Make sure these keys always quit
+		if common.Matches(msg, m.KeyMap.Cancel) {
 			m.canceled = true
+			// stop the running stages
+			m.cancel()
 			return m, tea.Quit
 		}
+		if common.Matches(msg, m.KeyMap.Help) {
+			m.help.ShowAll = !m.help.ShowAll
+		}
+	case stageMsg:
+		if msg.id != m.id {
+			return m, nil
+		}
+		if m.finishStage(msg) {
+			return m, m.quit()
+		}
+		// no more stages are started after a failure
+		if m.err != nil {
+			return m, nil
+		}
+		return m, m.dispatch()
+	case tickMsg:
+		// the stages still running after a failure keep reporting their
+		// progress, so the refresh only stops when none is in flight
+		if msg.id != m.id || m.loaded || (m.err != nil && len(m.running) == 0) {
+			return m, nil
+		}
+		m.frame++
+		m.updateProgress()
+		return m, m.tick()
+	}
+	return m, nil
+}
+
+// finishStage records the result of the finished stage, it returns
+// true if the execution of all stages has ended
+func (m *Model) finishStage(msg stageMsg) bool {
+	delete(m.running, msg.index)
+	m.durations[msg.index] = time.Since(m.stageStart[msg.index])
+	m.done++
+	m.message = msg.message
+	if msg.retries > 0 {
+		m.retries[msg.index] = msg.retries
+	}
+	if msg.err != nil {
+		m.errs[msg.index] = msg.err
+		// the skipped dependents are finished as well
+		m.done += m.skipDependents(msg.index)
+		// the failure of an optional stage is recorded, and the execution continues;
+		// Error() and Index() report the first failed required stage
+		if m.err == nil && !m.stages[msg.index].Optional {
+			m.err = msg.err
+			m.stageIndex = msg.index
+		}
+	} else {
+		// The progress bar steps a certain distance after each successful execution
+		m.succeeded[msg.index] = true
 	}
+	m.updateProgress()
 
-	if pf, ok := msg.(ProgressFunc); ok {
-		m.message, m.err = pf()
-		if m.err != nil {
-			return m, tea.Quit
+	// no more stages are started after a failure, the execution
+	// ends after the running stages finish
+	if m.err != nil {
+		if len(m.running) == 0 {
+			m.endTime = time.Now()
+			m.cancel()
+			return true
 		}
-		if m.stageIndex < len(m.Stages)-1 {
-			m.stageIndex++
-		}
-		if !m.loaded {
-			// The progress bar steps a certain distance after each successful execution
-			m.progress += float64(1) / float64(len(m.Stages))
-			// If all ProgressFunc has been executed, exit the TUI
-			if m.progress > 1 {
-				m.progress = 1
-				m.loaded = true
-				return m, tea.Quit
+		return false
+	}
+	// If all stages have been executed, the execution ends
+	if m.done == len(m.stages) {
+		m.loaded = true
+		m.endTime = time.Now()
+		m.cancel()
+		return true
+	}
+	return false
+}
+
+// updateProgress calculates the progress by the weights of the finished stages
+// and the partial progress reported by the running stages
+func (m *Model) updateProgress() {
+	var completed float64
+	for i, stage := range m.stages {
+		switch {
+		case m.succeeded[i], stage.Optional && m.errs[i] != nil, m.skipped[i] != nil:
+			completed += stage.Weight
+		case m.running[i]:
+			completed += stage.Weight * m.reporters[i].Fraction()
+		}
+	}
+	m.progress = math.Min(completed/m.totalWeight, 1)
+}
+
+// tick returns the command that triggers the next refresh
+func (m *Model) tick() tea.Cmd {
+	id := m.id
+	return tea.Tick(tickInterval, func(time.Time) tea.Msg {
+		return tickMsg{id: id}
+	})
+}
+
+// quit returns the command executed when all stages finish, the hosted
+// progress bar notifies the Group instead of quitting the program
+func (m *Model) quit() tea.Cmd {
+	if !m.hosted {
+		return tea.Quit
+	}
+	id := m.id
+	return func() tea.Msg {
+		return finishedMsg{id: id}
+	}
+}
+
+// stop cancels the running stages and ends the execution
+func (m *Model) stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
+// dispatch starts as many ready stages as the concurrency allows, the
+// stages are started in order once their dependencies have succeeded
+func (m *Model) dispatch() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, i := range m.startReady() {
+		cmds = append(cmds, m.runStage(i))
+	}
+	return tea.Batch(cmds...)
+}
+
+// startReady marks as many ready stages as the concurrency allows as
+// running, and returns their indexes
+func (m *Model) startReady() []int {
+	var started []int
+	for i := range m.stages {
+		if limit := m.concurrency(); limit > 0 && len(m.running) >= limit {
+			break
+		}
+		if !m.ready(i) {
+			continue
+		}
+		m.started[i] = true
+		m.running[i] = true
+		m.stageStart[i] = time.Now()
+		m.stageIndex = i
+		started = append(started, i)
+	}
+	return started
+}
+
+// runStage returns the command that executes the stage, tea runs
+// the commands in their own goroutines; the failed stage is retried
+// with an exponential backoff if it allows retries. The command must
+// not read the model, which is modified by Update at the same time
+func (m *Model) runStage(index int) tea.Cmd {
+	id, stage, reporter, parent, overall := m.id, m.stages[index], m.reporters[index], m.ctx, m.Timeout
+	return func() tea.Msg {
+		backoff := stage.Backoff
+		if backoff <= 0 {
+			backoff = DefaultBackoff
+		}
+
+		var retries int
+		for {
+			message, err := runAttempt(index, stage, reporter, parent, overall)
+			// a panic is a bug of the stage, retrying it makes no sense
+			var pe *PanicError
+			if err == nil || retries >= stage.Retries || parent.Err() != nil || errors.As(err, &pe) {
+				reporter.flushLog()
+				return stageMsg{id: id, index: index, message: message, err: err, retries: retries}
+			}
+
+			retries++
+			reporter.setRetry(retries, stage.Retries)
+			select {
+			case <-parent.Done():
+			case <-time.After(backoff):
 			}
+			backoff *= 2
 		}
 	}
+}
+
+// runAttempt executes the stage once within the stage timeout, overall is
+// the timeout of all stages
+func runAttempt(index int, stage Stage, reporter *Reporter, parent context.Context, overall time.Duration) (string, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if stage.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, stage.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
+	defer cancel()
+
+	type result struct {
+		message string
+		err     error
+	}
+	// the stage is executed in a separate goroutine, so that the stages which
+	// ignore the context can also be timed out; such a goroutine keeps running
+	// after the timeout, only its result is dropped
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- result{err: &PanicError{Index: index, Name: stage.Name, Value: v, Stack: debug.Stack()}}
+			}
+		}()
+		message, err := stage.Func(ctx, reporter)
+		done <- result{message: message, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res.err = ctx.Err()
+	}
+	if res.err != nil && ctx.Err() == context.DeadlineExceeded {
+		res.err = timeoutError(index, stage, parent, overall)
+	}
+	return res.message, res.err
+}
+
+// timeoutError returns the timeout error of the stage, it distinguishes
+// between the stage timeout and the overall timeout
+func timeoutError(index int, stage Stage, parent context.Context, overall time.Duration) error {
+	if parent.Err() == context.DeadlineExceeded {
+		return &TimeoutError{Index: index, Name: stage.Name, Timeout: overall, Overall: true}
+	}
+	return &TimeoutError{Index: index, Name: stage.Name, Timeout: stage.Timeout}
+}
+
+// stageName returns the name of the stage, the stages without a name
+// are named by their serial number
+func (m Model) stageName(index int) string {
+	if m.stages[index].Name != "" {
+		return m.stages[index].Name
+	}
+	return fmt.Sprintf("stage %d", index+1)
+}
 
-	// Return to the next ProgressFunc, trigger the traversal
-	return m, func() tea.Msg {
-		return m.Stages[m.stageIndex]
+// concurrency return the actual concurrency limit, 0 means no limit
+func (m Model) concurrency() int {
+	switch {
+	case m.Concurrency < 0:
+		return 0
+	case m.Concurrency > 0:
+		return m.Concurrency
 	}
+	for _, stage := range m.stages {
+		if len(stage.DependsOn) > 0 {
+			return 0
+		}
+	}
+	return 1
 }
 
 // initData initialize the data model, set the default value and
 // fix the wrong parameter settings during initialization
 func (m *Model) initData() {
+	if m.id == 0 {
+		m.id = nextID()
+	}
 	m.stageIndex = 0
+	m.message = m.InitMessage
+	m.errs = make(map[int]error)
+	m.stageStart = make(map[int]time.Time)
+	m.durations = make(map[int]time.Duration)
+	m.retries = make(map[int]int)
+	if m.Timeout > 0 {
+		m.ctx, m.cancel = context.WithTimeout(context.Background(), m.Timeout)
+	} else {
+		m.ctx, m.cancel = context.WithCancel(context.Background())
+	}
+	m.running = make(map[int]bool)
+	m.succeeded = make(map[int]bool)
+	m.started = make(map[int]bool)
+	m.skipped = make(map[int]error)
+	m.logs = &logBuffer{}
+	m.stages = append(m.stages[:0], m.Steps...)
+	if len(m.Steps) == 0 {
+		m.stages = FromFuncs(m.Stages...)
+	}
+	m.reporters = make([]*Reporter, len(m.stages))
+	m.totalWeight = 0
+	for i := range m.stages {
+		if m.stages[i].Weight <= 0 {
+			m.stages[i].Weight = 1
+		}
+		m.totalWeight += m.stages[i].Weight
+		m.reporters[i] = &Reporter{indeterminate: m.Indeterminate, log: m.logs}
+		// the lines of the concurrent stages are interleaved, so they are prefixed with the stage name
+		if m.concurrency() != 1 {
+			m.reporters[i].logPrefix = "[" + m.stageName(i) + "] "
+		}
+	}
+	if err := m.buildGraph(); err != nil {
+		m.err = err
+	}
 	if m.Width == 0 {
 		m.Width = 40
 	}
+	if m.KeyMap == nil {
+		km := DefaultKeyMap()
+		m.KeyMap = &km
+	}
+	m.help.Theme = m.Theme
+	m.help.Profile = m.ColorProfile
 	m.init = true
 }
 
-// Error returns the error generated during the execution of ProgressFunc
+// Error returns the error generated during the execution of the stages,
+// it is the error of the first failed stage when running concurrently
 func (m *Model) Error() error {
 	return m.err
 }
 
-// Index returns the index of the ProgressFunc currently executed
+// Errors returns the errors of all failed stages, keyed by the stage index
+func (m *Model) Errors() map[int]error {
+	return m.errs
+}
+
+// Index returns the index of the stage currently executed, it is the
+// index of the first failed stage if any stage failed
 func (m *Model) Index() int {
 	return m.stageIndex
 }
 
 // progressbar is responsible for rendering the progress bar UI
-func progressbar(width int, percent float64) string {
+func progressbar(width int, percent float64, theme common.Theme, p termenv.Profile) string {
 	w := float64(width)
 
 	fullSize := int(math.Round(w * percent))
+	cells := gradientCells(width, theme, p)
 	var fullCells string
 	for i := 0; i < fullSize; i++ {
-		fullCells += termenv.String(progressFullChar).Foreground(term.Color(makeRamp("#B14FFF", "#00FFA3", w)[i])).String()
+		fullCells += cells[i]
 	}
 
 	emptySize := int(w) - fullSize
-	emptyCells := strings.Repeat(progressEmpty, emptySize)
+	emptyCells := strings.Repeat(common.ColorWithProfile(p, progressEmptyChar, theme.Subtle), emptySize)
 
 	return fmt.Sprintf("%s%s %3.0f", fullCells, emptyCells, math.Round(percent*100))
 }
 
-// Utils
+// bouncingbar is responsible for rendering the indeterminate progress bar UI,
+// a short gradient block bounces between the two ends of the bar
+func bouncingbar(width int, frame int, theme common.Theme, p termenv.Profile) string {
+	block := width / 5
+	if block < 1 {
+		block = 1
+	}
+	span := width - block
+	pos := 0
+	if span > 0 {
+		pos = frame % (2 * span)
+		if pos > span {
+			pos = 2*span - pos
+		}
+	}
 
-// Color a string's foreground with the given value.
-func colorFg(val, color string) string {
-	return termenv.String(val).Foreground(term.Color(color)).String()
+	full := gradientCells(width, theme, p)
+	var cells string
+	for i := 0; i < width; i++ {
+		if i >= pos && i < pos+block {
+			cells += full[i]
+		} else {
+			cells += common.ColorWithProfile(p, progressEmptyChar, theme.Subtle)
+		}
+	}
+	return cells + " ---"
 }
 
-// Return a function that will colorize the foreground of a given string.
-func makeFgStyle(color string) func(string) string {
-	return termenv.Style{}.Foreground(term.Color(color)).Styled
+// gradientCells renders the filled cells of a bar with the given width, the
+// gradient degrades to the solid end color on the ANSI profile(16 colors can
+// not blend smoothly), and to the plain characters on the Ascii profile
+func gradientCells(width int, theme common.Theme, p termenv.Profile) []string {
+	cells := make([]string, width)
+	switch p {
+	case termenv.Ascii:
+		for i := range cells {
+			cells[i] = progressFullChar
+		}
+	case termenv.ANSI:
+		for i := range cells {
+			cells[i] = common.ColorWithProfile(p, progressFullChar, theme.GradientEnd)
+		}
+	default:
+		ramp := makeRamp(theme.GradientStart, theme.GradientEnd, float64(width))
+		for i := range cells {
+			cells[i] = common.ColorWithProfile(p, progressFullChar, ramp[i])
+		}
+	}
+	return cells
 }
 
-// Color a string's foreground and background with the given value.
-func makeFgBgStyle(fg, bg string) func(string) string {
-	return termenv.Style{}.
-		Foreground(term.Color(fg)).
-		Background(term.Color(bg)).
-		Styled
-}
+// Utils
 
 // Generate a blend of colors.
 func makeRamp(colorA, colorB string, steps float64) (s []string) {
@@ -195,10 +676,26 @@ func colorFloatToHex(f float64) (s string) {
 	return
 }
 
-func makeInfo(msg string) string {
-	return termenv.String(msg).Foreground(term.Color("2")).Bold().String()
+func (m Model) makeInfo(msg string) string {
+	return common.FontColorWithProfile(m.profile(), msg, m.CurrentTheme().Success)
+}
+
+func (m Model) makeError(msg string) string {
+	return common.FontColorWithProfile(m.profile(), msg, m.CurrentTheme().Error)
+}
+
+func (m Model) subtle(msg string) string {
+	return common.ColorWithProfile(m.profile(), msg, m.CurrentTheme().Subtle)
+}
+
+// profile returns the color profile of the progress bar, or the process-wide
+// color profile if not set
+func (m Model) profile() termenv.Profile {
+	return common.ProfileOrDefault(m.ColorProfile)
 }
 
-func makeError(msg string) string {
-	return termenv.String(msg).Foreground(term.Color("9")).Bold().String()
+// CurrentTheme return the theme used by the progress bar, it is the default
+// theme if Theme is not set
+func (m Model) CurrentTheme() common.Theme {
+	return common.ThemeOrDefault(m.Theme)
 }
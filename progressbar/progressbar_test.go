@@ -0,0 +1,361 @@
+package progressbar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var errBoom = errors.New("boom")
+
+// okStage returns a named stage which succeeds after a short delay
+func okStage(name string, deps ...string) Stage {
+	return Stage{Name: name, DependsOn: deps, Func: func(context.Context, *Reporter) (string, error) {
+		time.Sleep(time.Millisecond)
+		return name + " done", nil
+	}}
+}
+
+// failStage returns a named stage which fails
+func failStage(name string, deps ...string) Stage {
+	return Stage{Name: name, DependsOn: deps, Func: func(context.Context, *Reporter) (string, error) {
+		return "", errBoom
+	}}
+}
+
+// waitStage returns a named stage which waits until its context is done
+func waitStage(name string) Stage {
+	return Stage{Name: name, Func: func(ctx context.Context, _ *Reporter) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}}
+}
+
+// names returns the names of the stage results
+func names(results []StageResult) []string {
+	var s []string
+	for _, r := range results {
+		s = append(s, r.Name)
+	}
+	return s
+}
+
+func TestRunPlain(t *testing.T) {
+	tests := []struct {
+		name  string
+		model func() *Model
+		check func(t *testing.T, m *Model, err error)
+	}{
+		{
+			name: "cycle",
+			model: func() *Model {
+				return &Model{Steps: []Stage{okStage("a", "c"), okStage("b", "a"), okStage("c", "b"), okStage("d")}}
+			},
+			check: func(t *testing.T, m *Model, err error) {
+				var ce *CycleError
+				if !errors.As(err, &ce) {
+					t.Fatalf("got %v, want *CycleError", err)
+				}
+				if want := []string{"a", "c", "b", "a"}; !reflect.DeepEqual(ce.Names, want) {
+					t.Errorf("cycle %v, want %v", ce.Names, want)
+				}
+				if s := m.Summary(); len(s.Succeeded) != 0 {
+					t.Errorf("stages %v started despite the cycle", names(s.Succeeded))
+				}
+			},
+		},
+		{
+			name: "unknown dependency",
+			model: func() *Model {
+				return &Model{Steps: []Stage{okStage("a"), okStage("b", "x")}}
+			},
+			check: func(t *testing.T, _ *Model, err error) {
+				if err == nil || !strings.Contains(err.Error(), `unknown stage "x"`) {
+					t.Errorf("got %v, want the unknown stage error", err)
+				}
+			},
+		},
+		{
+			name: "duplicate dependency name",
+			model: func() *Model {
+				return &Model{Steps: []Stage{okStage("a"), okStage("a"), okStage("b", "a")}}
+			},
+			check: func(t *testing.T, _ *Model, err error) {
+				if err == nil || !strings.Contains(err.Error(), "not unique") {
+					t.Errorf("got %v, want the duplicate name error", err)
+				}
+			},
+		},
+		{
+			name: "unused duplicate name",
+			model: func() *Model {
+				return &Model{Steps: []Stage{okStage("a"), okStage("a"), okStage("b")}}
+			},
+			check: func(t *testing.T, m *Model, err error) {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if s := m.Summary(); len(s.Succeeded) != 3 {
+					t.Errorf("succeeded %v, want 3 stages", names(s.Succeeded))
+				}
+			},
+		},
+		{
+			name: "failed dependency",
+			model: func() *Model {
+				return &Model{Steps: []Stage{failStage("a"), okStage("b", "a"), okStage("c", "b"), okStage("d")}}
+			},
+			check: func(t *testing.T, m *Model, err error) {
+				if !errors.Is(err, errBoom) {
+					t.Errorf("got %v, want %v", err, errBoom)
+				}
+				s := m.Summary()
+				if want := []string{"b", "c"}; !reflect.DeepEqual(names(s.Skipped), want) {
+					t.Fatalf("skipped %v, want %v", names(s.Skipped), want)
+				}
+				for _, r := range s.Skipped {
+					var se *SkipError
+					if !errors.As(r.Err, &se) || se.Dependency != "a" {
+						t.Errorf("%s skipped with %v, want *SkipError of a", r.Name, r.Err)
+					}
+				}
+				if want := []string{"a"}; !reflect.DeepEqual(names(s.Failed), want) {
+					t.Errorf("failed %v, want %v", names(s.Failed), want)
+				}
+			},
+		},
+		{
+			name: "optional failure",
+			model: func() *Model {
+				opt := failStage("a")
+				opt.Optional = true
+				return &Model{Steps: []Stage{opt, okStage("b", "c"), okStage("c")}}
+			},
+			check: func(t *testing.T, m *Model, err error) {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				s := m.Summary()
+				if len(s.Failed) != 1 || !s.Failed[0].Optional || len(s.Succeeded) != 2 {
+					t.Errorf("failed %v, succeeded %v", names(s.Failed), names(s.Succeeded))
+				}
+				if m.Progress() != 1 {
+					t.Errorf("progress %v, want 1", m.Progress())
+				}
+			},
+		},
+		{
+			name: "retries",
+			model: func() *Model {
+				var attempts int32
+				return &Model{Steps: []Stage{{
+					Name:    "flaky",
+					Retries: 3,
+					Backoff: time.Millisecond,
+					Func: func(context.Context, *Reporter) (string, error) {
+						if atomic.AddInt32(&attempts, 1) < 3 {
+							return "", errBoom
+						}
+						return "ok", nil
+					},
+				}, {Name: "broken", Retries: 1, Backoff: time.Millisecond, Optional: true, Func: failStage("").Func}}}
+			},
+			check: func(t *testing.T, m *Model, err error) {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				s := m.Summary()
+				if len(s.Retried) != 2 || s.Retried[0].Retries != 2 || s.Retried[1].Retries != 1 {
+					t.Errorf("retried %+v, want flaky 2 times and broken 1 time", s.Retried)
+				}
+				if len(s.Succeeded) != 1 || len(s.Failed) != 1 {
+					t.Errorf("succeeded %v, failed %v", names(s.Succeeded), names(s.Failed))
+				}
+			},
+		},
+		{
+			name: "stage timeout",
+			model: func() *Model {
+				st := waitStage("slow")
+				st.Timeout = 10 * time.Millisecond
+				return &Model{Steps: []Stage{st}, Timeout: time.Minute}
+			},
+			check: func(t *testing.T, _ *Model, err error) {
+				var te *TimeoutError
+				if !errors.As(err, &te) || te.Overall || te.Timeout != 10*time.Millisecond {
+					t.Errorf("got %#v, want the stage timeout", err)
+				}
+			},
+		},
+		{
+			name: "overall timeout",
+			model: func() *Model {
+				st := waitStage("slow")
+				st.Timeout = time.Minute
+				return &Model{Steps: []Stage{okStage("fast"), st}, Timeout: 10 * time.Millisecond, Concurrency: -1}
+			},
+			check: func(t *testing.T, m *Model, err error) {
+				var te *TimeoutError
+				if !errors.As(err, &te) || !te.Overall || te.Timeout != 10*time.Millisecond || te.Name != "slow" {
+					t.Errorf("got %#v, want the overall timeout", err)
+				}
+				if s := m.Summary(); len(s.Succeeded) != 1 {
+					t.Errorf("succeeded %v, want fast", names(s.Succeeded))
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.model()
+			var out bytes.Buffer
+			err := m.RunPlain(&out)
+			if err != m.Error() {
+				t.Errorf("RunPlain() = %v, Error() = %v", err, m.Error())
+			}
+			tt.check(t, m, err)
+		})
+	}
+}
+
+func TestRunPlainConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		stages      []string
+		deps        map[string][]string
+		want        int32
+	}{
+		{name: "sequential by default", stages: []string{"a", "b", "c", "d"}, want: 1},
+		{name: "limited", concurrency: 2, stages: []string{"a", "b", "c", "d"}, want: 2},
+		{name: "unlimited", concurrency: -1, stages: []string{"a", "b", "c", "d"}, want: 4},
+		{
+			name:   "dependency graph",
+			stages: []string{"a", "b", "c", "d"},
+			deps:   map[string][]string{"b": {"a"}, "c": {"a"}, "d": {"b", "c"}},
+			want:   2,
+		},
+		{
+			name:        "limited dependency graph",
+			concurrency: 1,
+			stages:      []string{"a", "b", "c", "d"},
+			deps:        map[string][]string{"b": {"a"}, "c": {"a"}, "d": {"b", "c"}},
+			want:        1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, peak int32
+			var order []string
+			orderc := make(chan string, len(tt.stages))
+			m := &Model{Concurrency: tt.concurrency}
+			for _, name := range tt.stages {
+				name := name
+				m.Steps = append(m.Steps, Stage{Name: name, DependsOn: tt.deps[name], Func: func(context.Context, *Reporter) (string, error) {
+					n := atomic.AddInt32(&running, 1)
+					for p := atomic.LoadInt32(&peak); n > p && !atomic.CompareAndSwapInt32(&peak, p, n); p = atomic.LoadInt32(&peak) {
+					}
+					orderc <- name
+					time.Sleep(10 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+					return "", nil
+				}})
+			}
+
+			if err := m.RunPlain(&bytes.Buffer{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			close(orderc)
+			for name := range orderc {
+				order = append(order, name)
+			}
+			if peak != tt.want {
+				t.Errorf("%d stages ran at once, want %d", peak, tt.want)
+			}
+			// the dependencies start before their dependents
+			started := make(map[string]bool)
+			for _, name := range order {
+				for _, dep := range tt.deps[name] {
+					if !started[dep] {
+						t.Errorf("%s started before its dependency %s", name, dep)
+					}
+				}
+				started[name] = true
+			}
+		})
+	}
+}
+
+// runProgram drives the model like a bubbletea program: the commands run in
+// their own goroutines and their messages are sent to Update one at a time,
+// the refresh ticks are dropped; it returns after the model quits
+func runProgram(t *testing.T, m *Model) {
+	msgs := make(chan tea.Msg)
+	var exec func(cmd tea.Cmd)
+	exec = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		go func() {
+			msg := cmd()
+			// tea.Batch returns an unexported slice of commands
+			if v := reflect.ValueOf(msg); v.Kind() == reflect.Slice {
+				for i := 0; i < v.Len(); i++ {
+					exec(v.Index(i).Interface().(tea.Cmd))
+				}
+				return
+			}
+			if _, ok := msg.(tickMsg); ok {
+				return
+			}
+			msgs <- msg
+		}()
+	}
+
+	exec(m.Init())
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case msg := <-msgs:
+			if msg == tea.Quit() {
+				return
+			}
+			_, cmd := m.Update(msg)
+			exec(cmd)
+		case <-timeout:
+			t.Fatal("the program does not quit")
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	m := &Model{Steps: []Stage{
+		okStage("a"), okStage("b", "a"), okStage("c", "a"), failStage("d", "b"), okStage("e", "d"), okStage("f", "c"),
+	}}
+	runProgram(t, m)
+
+	if !errors.Is(m.Error(), errBoom) {
+		t.Errorf("Error() = %v, want %v", m.Error(), errBoom)
+	}
+	// f is also skipped if c is still running when d fails
+	var se *SkipError
+	if r := m.Results()[4]; r.Status != StageSkipped || !errors.As(r.Err, &se) || se.Dependency != "d" {
+		t.Errorf("e %s with %v, want skipped by d", r.Status, r.Err)
+	}
+	s := m.Summary()
+	if want := []string{"d"}; !reflect.DeepEqual(names(s.Failed), want) {
+		t.Errorf("failed %v, want %v", names(s.Failed), want)
+	}
+	if eta, ok := m.ETA(); !ok || eta != 0 {
+		t.Errorf("ETA() = %v, %v after the execution ended", eta, ok)
+	}
+}
@@ -0,0 +1,265 @@
+package progressbar
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// StageFunc is the extended form of ProgressFunc, the stage can report its
+// partial progress through the Reporter while it is running; the context is
+// cancelled when the user quits or the timeout elapses, the stage should
+// return as soon as possible after that. A stage ignoring the context is not
+// stopped: its goroutine keeps running(and leaks until the function returns),
+// only its result is dropped
+type StageFunc func(ctx context.Context, r *Reporter) (string, error)
+
+// Stage is a stage with a weight, the progress bar steps a distance
+// proportional to the weight after the stage is executed
+type Stage struct {
+	// Name is displayed while the stage is running
+	Name string
+	// Weight is the relative amount of work of the stage, defaults to 1
+	Weight float64
+	// Func is the function executed by the stage
+	Func StageFunc
+	// Timeout is the maximum execution time of each attempt of the stage, 0 means no limit
+	Timeout time.Duration
+	// Retries is the number of times the stage is retried after a failure
+	Retries int
+	// Backoff is the delay before the first retry, it doubles after each retry,
+	// defaults to DefaultBackoff
+	Backoff time.Duration
+	// Optional indicates that the failure of the stage is recorded but does
+	// not stop the execution of the other stages
+	Optional bool
+	// DependsOn are the names of the stages that must succeed before the stage
+	// starts, the stage is skipped if any of them fails
+	DependsOn []string
+}
+
+// Stage converts the ProgressFunc to a Stage with the default weight
+func (pf ProgressFunc) Stage() Stage {
+	return Stage{
+		Weight: 1,
+		Func: func(_ context.Context, _ *Reporter) (string, error) {
+			return pf()
+		},
+	}
+}
+
+// FromFuncs converts the simple ProgressFuncs to the stages with the default weight
+func FromFuncs(funcs ...ProgressFunc) []Stage {
+	stages := make([]Stage, 0, len(funcs))
+	for _, pf := range funcs {
+		stages = append(stages, pf.Stage())
+	}
+	return stages
+}
+
+// TimeoutError is the error of a stage that does not finish within
+// the stage timeout or the overall timeout of the progress bar
+type TimeoutError struct {
+	// Index is the index of the stage
+	Index int
+	// Name is the name of the stage
+	Name string
+	// Timeout is the timeout that elapsed
+	Timeout time.Duration
+	// Overall indicates whether the overall timeout elapsed
+	Overall bool
+}
+
+// Error implements the error interface
+func (e *TimeoutError) Error() string {
+	name := e.Name
+	if name == "" {
+		name = fmt.Sprintf("stage %d", e.Index+1)
+	}
+	if e.Overall {
+		return fmt.Sprintf("%s timed out: the overall timeout %s elapsed", name, e.Timeout)
+	}
+	return fmt.Sprintf("%s timed out after %s", name, e.Timeout)
+}
+
+// Unwrap returns context.DeadlineExceeded, so that errors.Is works with TimeoutError
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// PanicError is the error of a stage that panics, the panic is recovered
+// so that the program can exit and restore the terminal
+type PanicError struct {
+	// Index is the index of the stage
+	Index int
+	// Name is the name of the stage
+	Name string
+	// Value is the value passed to panic
+	Value interface{}
+	// Stack is the stack trace of the goroutine that panicked
+	Stack []byte
+}
+
+// Error implements the error interface, the stack trace is not included
+func (e *PanicError) Error() string {
+	name := e.Name
+	if name == "" {
+		name = fmt.Sprintf("stage %d", e.Index+1)
+	}
+	return fmt.Sprintf("%s panicked: %v", name, e.Value)
+}
+
+// Unwrap returns the value passed to panic if it is an error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Reporter reports the progress of a running stage, it is safe
+// to be used from multiple goroutines. It is also an io.Writer, the
+// written lines are displayed in the log region under the bar
+type Reporter struct {
+	mu         sync.Mutex
+	fraction   float64
+	message    string
+	bytes      int64
+	totalBytes int64
+	// indeterminate indicates that the total work of the stage is unknown
+	indeterminate bool
+	// active indicates that the stage has reported something
+	active bool
+	// retry is the current retry of the stage, maxRetries is the retry limit
+	retry      int
+	maxRetries int
+	// log is the log buffer of the progress bar, partial is the incomplete line
+	// written to the reporter, logPrefix is prepended to each line
+	log       *logBuffer
+	partial   []byte
+	logPrefix string
+}
+
+// Report sets the completed fraction of the stage, the value is limited to [0, 1]
+func (r *Reporter) Report(fraction float64) {
+	if fraction < 0 {
+		fraction = 0
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	r.mu.Lock()
+	r.fraction = fraction
+	r.indeterminate = false
+	r.active = true
+	r.mu.Unlock()
+}
+
+// Message sets the message displayed while the stage is running
+func (r *Reporter) Message(msg string) {
+	r.mu.Lock()
+	r.message = msg
+	r.active = true
+	r.mu.Unlock()
+}
+
+// SetTotalBytes sets the total bytes of the stage, the completed fraction
+// is calculated by the bytes reported through AddBytes after that; if the
+// total is unknown(less than or equal to 0), the stage is indeterminate
+func (r *Reporter) SetTotalBytes(total int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.totalBytes = total
+	r.indeterminate = total <= 0
+	r.active = true
+	r.updateFraction()
+}
+
+// AddBytes adds the bytes processed by the stage, they are used to calculate
+// the throughput and the completed fraction if the total bytes are known
+func (r *Reporter) AddBytes(n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bytes += n
+	r.active = true
+	r.updateFraction()
+}
+
+// Bytes returns the processed bytes and the total bytes of the stage
+func (r *Reporter) Bytes() (done, total int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bytes, r.totalBytes
+}
+
+// updateFraction updates the completed fraction by the processed bytes,
+// the caller must hold the lock
+func (r *Reporter) updateFraction() {
+	if r.totalBytes > 0 {
+		r.fraction = math.Min(float64(r.bytes)/float64(r.totalBytes), 1)
+	}
+}
+
+// Fraction returns the completed fraction of the stage
+func (r *Reporter) Fraction() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.fraction
+}
+
+// SetIndeterminate marks the total work of the stage as unknown, the bar bounces
+// until the stage reports its progress through Report or SetTotalBytes
+func (r *Reporter) SetIndeterminate() {
+	r.mu.Lock()
+	r.indeterminate = true
+	r.active = true
+	r.mu.Unlock()
+}
+
+// Indeterminate returns whether the total work of the stage is unknown
+func (r *Reporter) Indeterminate() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.indeterminate
+}
+
+// runningView renders the progress reported by the running stage, active
+// is false if the stage has not reported anything and is not indeterminate
+func (r *Reporter) runningView() (view string, active bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.active && !r.indeterminate {
+		return "", false
+	}
+
+	switch {
+	case r.indeterminate && r.bytes > 0:
+		view = formatBytes(float64(r.bytes))
+	case r.indeterminate:
+	case r.totalBytes > 0:
+		view = fmt.Sprintf("%3.0f%% %s/%s", math.Round(r.fraction*100), formatBytes(float64(r.bytes)), formatBytes(float64(r.totalBytes)))
+	default:
+		view = fmt.Sprintf("%3.0f%%", math.Round(r.fraction*100))
+	}
+	if r.message != "" {
+		view += " " + r.message
+	}
+	if r.retry > 0 {
+		view += fmt.Sprintf(" (retry %d/%d)", r.retry, r.maxRetries)
+	}
+	return view, true
+}
+
+// setRetry records the current retry of the stage, the reported
+// progress is reset for the new attempt
+func (r *Reporter) setRetry(retry, maxRetries int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retry = retry
+	r.maxRetries = maxRetries
+	r.fraction = 0
+	r.bytes = 0
+	r.active = true
+}
@@ -0,0 +1,153 @@
+package progressbar
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Progress returns the completed fraction of all stages
+func (m Model) Progress() float64 {
+	return m.progress
+}
+
+// BarView renders the progress bar and the percentage, the bar bounces
+// while an indeterminate stage is running
+func (m Model) BarView() string {
+	if m.IsIndeterminate() {
+		return bouncingbar(m.Width, m.frame, m.CurrentTheme(), m.profile())
+	}
+	return progressbar(m.Width, m.progress, m.CurrentTheme(), m.profile()) + "%"
+}
+
+// IsIndeterminate returns whether any running stage is indeterminate
+func (m Model) IsIndeterminate() bool {
+	for i := range m.running {
+		if m.reporters[i].Indeterminate() {
+			return true
+		}
+	}
+	return false
+}
+
+// Elapsed returns the time elapsed since the first stage started, it
+// stops increasing after the execution ends
+func (m Model) Elapsed() time.Duration {
+	if m.startTime.IsZero() {
+		return 0
+	}
+	if !m.endTime.IsZero() {
+		return m.endTime.Sub(m.startTime)
+	}
+	return time.Since(m.startTime)
+}
+
+// ETA returns the estimated remaining time, it is estimated by the execution time
+// per unit of weight of the finished stages, or by the elapsed time and the partial
+// progress if no stage has finished; ok is false if it can not be estimated yet.
+// It is 0 after the execution ends, whether it succeeded, failed or was cancelled
+func (m Model) ETA() (eta time.Duration, ok bool) {
+	if m.loaded || m.ended() {
+		return 0, true
+	}
+
+	remaining := m.totalWeight * (1 - m.progress)
+	var weight float64
+	var cost time.Duration
+	for i := range m.succeeded {
+		weight += m.stages[i].Weight
+		cost += m.durations[i]
+	}
+	if weight > 0 {
+		// the running stages share the remaining work
+		parallel := math.Max(1, float64(len(m.stages)-m.done))
+		if limit := m.concurrency(); limit > 0 {
+			parallel = math.Min(parallel, float64(limit))
+		}
+		return time.Duration(float64(cost) / weight * remaining / parallel), true
+	}
+	if m.progress > 0 {
+		elapsed := m.Elapsed()
+		return time.Duration(float64(elapsed) * (1 - m.progress) / m.progress), true
+	}
+	return 0, false
+}
+
+// StageDurations returns the execution time of the finished stages, keyed by the stage index
+func (m Model) StageDurations() map[int]time.Duration {
+	return m.durations
+}
+
+// Throughput returns the bytes processed per second by all stages
+func (m Model) Throughput() float64 {
+	elapsed := m.Elapsed().Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	var bytes int64
+	for _, r := range m.reporters {
+		done, _ := r.Bytes()
+		bytes += done
+	}
+	return float64(bytes) / elapsed
+}
+
+// statsView renders the enabled statistics under the bar
+func (m Model) statsView() string {
+	var items []string
+	if m.ShowElapsed {
+		items = append(items, "elapsed "+formatDuration(m.Elapsed()))
+	}
+	if m.ShowETA {
+		if eta, ok := m.ETA(); ok {
+			items = append(items, "eta "+formatDuration(eta))
+		} else {
+			items = append(items, "eta --")
+		}
+	}
+	if m.ShowThroughput {
+		items = append(items, formatBytes(m.Throughput())+"/s")
+	}
+
+	var lines []string
+	if len(items) > 0 {
+		lines = append(lines, m.subtle(strings.Join(items, " • ")))
+	}
+	if m.ShowDurations {
+		for i := range m.stages {
+			d, ok := m.durations[i]
+			if !ok {
+				continue
+			}
+			mark := "✔"
+			if m.errs[i] != nil {
+				mark = "✘"
+			}
+			lines = append(lines, m.subtle(fmt.Sprintf("%s %s %s", mark, m.stageName(i), formatDuration(d))))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatDuration formats the duration with a precision suitable for display
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+// formatBytes formats the bytes in the binary units
+func formatBytes(b float64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%.0f B", b)
+	}
+	units := "KMGTPE"
+	i := 0
+	for b /= unit; b >= unit && i < len(units)-1; b /= unit {
+		i++
+	}
+	return fmt.Sprintf("%.1f %ciB", b, units[i])
+}
@@ -0,0 +1,152 @@
+package progressbar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StageStatus is the execution status of a stage
+type StageStatus int
+
+const (
+	// StagePending the stage is waiting to be executed
+	StagePending StageStatus = iota
+	// StageRunning the stage is being executed
+	StageRunning
+	// StageSucceeded the stage finished without error
+	StageSucceeded
+	// StageFailed the stage returned an error after all retries
+	StageFailed
+	// StageSkipped the stage was not executed because the execution ended
+	// or one of its dependencies failed
+	StageSkipped
+)
+
+// String returns the name of the status
+func (s StageStatus) String() string {
+	switch s {
+	case StageRunning:
+		return "running"
+	case StageSucceeded:
+		return "succeeded"
+	case StageFailed:
+		return "failed"
+	case StageSkipped:
+		return "skipped"
+	default:
+		return "pending"
+	}
+}
+
+// StageResult is the execution result of a stage
+type StageResult struct {
+	// Index is the index of the stage in Steps
+	Index int
+	// Name is the name of the stage
+	Name string
+	// Status is the execution status of the stage
+	Status StageStatus
+	// Optional indicates whether the stage is optional
+	Optional bool
+	// Retries is the number of times the stage was retried
+	Retries int
+	// Duration is the execution time of the stage, including the retries
+	Duration time.Duration
+	// Err is the error of the failed stage, or the reason(*SkipError) of
+	// the stage skipped because one of its dependencies failed
+	Err error
+}
+
+// Summary groups the stage results by their status, a stage that
+// succeeded or failed after retries is also listed in Retried
+type Summary struct {
+	Succeeded []StageResult
+	Retried   []StageResult
+	Failed    []StageResult
+	Skipped   []StageResult
+}
+
+// Results returns the execution results of all stages
+func (m Model) Results() []StageResult {
+	results := make([]StageResult, 0, len(m.stages))
+	for i, stage := range m.stages {
+		r := StageResult{
+			Index:    i,
+			Name:     m.stageName(i),
+			Optional: stage.Optional,
+			Retries:  m.retries[i],
+			Duration: m.durations[i],
+			Err:      m.errs[i],
+		}
+		if r.Err == nil {
+			r.Err = m.skipped[i]
+		}
+		switch {
+		case m.succeeded[i]:
+			r.Status = StageSucceeded
+		case m.errs[i] != nil:
+			r.Status = StageFailed
+		case m.skipped[i] != nil:
+			r.Status = StageSkipped
+		case m.running[i]:
+			r.Status = StageRunning
+		case m.ended():
+			r.Status = StageSkipped
+		default:
+			r.Status = StagePending
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// Summary returns the execution results grouped by their status
+func (m Model) Summary() Summary {
+	var s Summary
+	for _, r := range m.Results() {
+		switch r.Status {
+		case StageSucceeded:
+			s.Succeeded = append(s.Succeeded, r)
+		case StageFailed:
+			s.Failed = append(s.Failed, r)
+		case StageSkipped:
+			s.Skipped = append(s.Skipped, r)
+		}
+		if r.Retries > 0 {
+			s.Retried = append(s.Retried, r)
+		}
+	}
+	return s
+}
+
+// ended determine whether the execution has ended
+func (m Model) ended() bool {
+	return !m.endTime.IsZero() || m.canceled
+}
+
+// summaryView renders the summary of the stages after the execution ends
+func (m Model) summaryView() string {
+	if !m.ShowSummary || !m.ended() {
+		return ""
+	}
+
+	s := m.Summary()
+	lines := []string{m.subtle(fmt.Sprintf("✔ %d succeeded • ↻ %d retried • ✘ %d failed • ⊘ %d skipped",
+		len(s.Succeeded), len(s.Retried), len(s.Failed), len(s.Skipped)))}
+	for _, r := range s.Failed {
+		line := fmt.Sprintf("✘ %s: %s", r.Name, r.Err)
+		if r.Optional {
+			line += " (optional)"
+		}
+		lines = append(lines, m.makeError(line))
+	}
+	for _, r := range s.Skipped {
+		if r.Err != nil {
+			lines = append(lines, m.subtle("⊘ "+r.Err.Error()))
+			continue
+		}
+		lines = append(lines, m.subtle(fmt.Sprintf("⊘ %s skipped", r.Name)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,182 @@
+package prompt
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mritd/bubbles/common"
+)
+
+// DefaultMaxSuggestions is the default number of the suggestions displayed under the input
+const DefaultMaxSuggestions = 5
+
+// lastID is the last id assigned to a prompt, the suggestions are
+// returned asynchronously with the id of the prompt
+var lastID int64
+
+// nextID returns the next prompt id
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// suggestionsMsg carries the suggestions returned by the CompleteFunc
+type suggestionsMsg struct {
+	id    int
+	input string
+	items []string
+}
+
+// refreshSuggestions updates the suggestions for the current input, the
+// static Completions are filtered immediately, and the CompleteFunc is
+// called in a command so that a slow source does not block the input
+func (m *Model) refreshSuggestions() tea.Cmd {
+	m.selected = -1
+	if !m.completable() {
+		m.suggestions = nil
+		return nil
+	}
+
+	input := m.input.Value()
+	if m.CompleteFunc == nil {
+		m.suggestions = filterSuggestions(input, m.Completions)
+		return nil
+	}
+	id, f := m.id, m.CompleteFunc
+	return func() tea.Msg {
+		return suggestionsMsg{id: id, input: input, items: f(input)}
+	}
+}
+
+// completable determine whether the completion is enabled, the hidden
+// input is never completed
+func (m Model) completable() bool {
+	return m.EchoMode == EchoNormal && (len(m.Completions) > 0 || m.CompleteFunc != nil)
+}
+
+// filterSuggestions returns the candidates matching the input, the candidates
+// starting with the input come first, followed by the fuzzy matches by score
+func filterSuggestions(input string, candidates []string) []string {
+	if input == "" {
+		return nil
+	}
+
+	type match struct {
+		s     string
+		score int
+	}
+	var prefixed []string
+	var fuzzy []match
+	for _, c := range candidates {
+		if c == input {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(c), strings.ToLower(input)) {
+			prefixed = append(prefixed, c)
+			continue
+		}
+		if _, score, ok := common.FuzzyMatch(input, c); ok {
+			fuzzy = append(fuzzy, match{s: c, score: score})
+		}
+	}
+	sort.SliceStable(fuzzy, func(i, j int) bool {
+		return fuzzy[i].score > fuzzy[j].score
+	})
+	for _, f := range fuzzy {
+		prefixed = append(prefixed, f.s)
+	}
+	return prefixed
+}
+
+// cycleSuggestion highlights the next(or the previous) suggestion
+func (m *Model) cycleSuggestion(step int) {
+	n := len(m.suggestions)
+	if n == 0 {
+		return
+	}
+	if m.selected < 0 {
+		if step > 0 {
+			m.selected = 0
+		} else {
+			m.selected = n - 1
+		}
+		return
+	}
+	m.selected = ((m.selected+step)%n + n) % n
+}
+
+// current returns the highlighted suggestion, or the top suggestion if
+// none is highlighted
+func (m Model) current() (string, bool) {
+	if len(m.suggestions) == 0 {
+		return "", false
+	}
+	if m.selected >= 0 {
+		return m.suggestions[m.selected], true
+	}
+	return m.suggestions[0], true
+}
+
+// acceptSuggestion replaces the input with the highlighted(or the top) suggestion
+func (m *Model) acceptSuggestion() (tea.Cmd, bool) {
+	s, ok := m.current()
+	if !ok {
+		return nil, false
+	}
+	m.input.SetValue(s)
+	m.input.CursorEnd()
+	m.showErr = false
+	return tea.Batch(m.validate(s), m.refreshSuggestions()), true
+}
+
+// ghostView renders the rest of the current suggestion after the input
+// as an inline preview, it is only displayed if the suggestion starts
+// with the input and the cursor is at the end
+func (m Model) ghostView() string {
+	s, ok := m.current()
+	input := m.input.Value()
+	if !ok || input == "" || m.input.Cursor() != len([]rune(input)) {
+		return ""
+	}
+	if !strings.HasPrefix(strings.ToLower(s), strings.ToLower(input)) {
+		return ""
+	}
+	return m.render(styleGhost, string([]rune(s)[len([]rune(input)):]))
+}
+
+// suggestionsView renders the suggestion list under the input
+func (m Model) suggestionsView() string {
+	if len(m.suggestions) == 0 {
+		return ""
+	}
+
+	max := m.MaxSuggestions
+	if max <= 0 {
+		max = DefaultMaxSuggestions
+	}
+	// the list scrolls to keep the highlighted suggestion visible
+	start := 0
+	if m.selected >= max {
+		start = m.selected - max + 1
+	}
+	end := start + max
+	if end > len(m.suggestions) {
+		end = len(m.suggestions)
+	}
+
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		if i == m.selected {
+			b.WriteString("  " + m.render(styleSuggestionSelected, "» "+m.suggestions[i]) + "\n")
+			continue
+		}
+		b.WriteString("    " + m.render(styleSuggestion, m.suggestions[i]) + "\n")
+	}
+	return b.String()
+}
+
+// Suggestions return the suggestions for the current input
+func (m Model) Suggestions() []string {
+	return m.suggestions
+}
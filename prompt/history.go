@@ -0,0 +1,297 @@
+package prompt
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mritd/bubbles/common"
+)
+
+const (
+	// DefaultHistoryKey is the history key of the prompts without a HistoryKey
+	DefaultHistoryKey = "default"
+	// DefaultHistorySize is the default maximum number of the entries kept for each key
+	DefaultHistorySize = 500
+)
+
+// HistoryStore stores the input history of the prompts, the entries are
+// keyed by the prompt id(Model.HistoryKey) and ordered from old to new
+type HistoryStore interface {
+	// Load returns the entries of the key
+	Load(key string) ([]string, error)
+	// Add appends the entry to the key, the duplicate entry is moved to
+	// the end, and the oldest entries are dropped beyond the maximum size
+	Add(key, entry string) error
+}
+
+// addEntry appends the entry without duplicates and keeps the last max entries
+func addEntry(entries []string, entry string, max int) []string {
+	if max <= 0 {
+		max = DefaultHistorySize
+	}
+	result := make([]string, 0, len(entries)+1)
+	for _, e := range entries {
+		if e != entry {
+			result = append(result, e)
+		}
+	}
+	result = append(result, entry)
+	if len(result) > max {
+		result = result[len(result)-max:]
+	}
+	return result
+}
+
+// MemoryHistory is a HistoryStore kept in memory, it is lost when the process exits
+type MemoryHistory struct {
+	// MaxSize is the maximum number of the entries kept for each key,
+	// defaults to DefaultHistorySize
+	MaxSize int
+
+	mu      sync.Mutex
+	entries map[string][]string
+}
+
+// NewMemoryHistory returns a MemoryHistory with the given maximum size
+func NewMemoryHistory(maxSize int) *MemoryHistory {
+	return &MemoryHistory{MaxSize: maxSize}
+}
+
+// Load returns the entries of the key
+func (h *MemoryHistory) Load(key string) ([]string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.entries[key]...), nil
+}
+
+// Add appends the entry to the key
+func (h *MemoryHistory) Add(key, entry string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.entries == nil {
+		h.entries = make(map[string][]string)
+	}
+	h.entries[key] = addEntry(h.entries[key], entry, h.MaxSize)
+	return nil
+}
+
+// FileHistory is a HistoryStore persisted in a JSON file, the entries
+// of all keys are stored in the same file
+type FileHistory struct {
+	// Path is the path of the history file, it is created on the first Add
+	Path string
+	// MaxSize is the maximum number of the entries kept for each key,
+	// defaults to DefaultHistorySize
+	MaxSize int
+
+	mu sync.Mutex
+}
+
+// NewFileHistory returns a FileHistory with the given path and maximum size
+func NewFileHistory(path string, maxSize int) *FileHistory {
+	return &FileHistory{Path: path, MaxSize: maxSize}
+}
+
+// Load returns the entries of the key, a missing file has no entries
+func (h *FileHistory) Load(key string) ([]string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	all, err := h.read()
+	if err != nil {
+		return nil, err
+	}
+	return all[key], nil
+}
+
+// Add appends the entry to the key, the file is replaced atomically
+func (h *FileHistory) Add(key, entry string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	all, err := h.read()
+	if err != nil {
+		return err
+	}
+	all[key] = addEntry(all[key], entry, h.MaxSize)
+
+	bs, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(h.Path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(h.Path), filepath.Base(h.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(bs); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), h.Path)
+}
+
+// read reads the entries of all keys, the caller must hold the lock
+func (h *FileHistory) read() (map[string][]string, error) {
+	all := make(map[string][]string)
+	bs, err := os.ReadFile(h.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) == 0 {
+		return all, nil
+	}
+	if err = json.Unmarshal(bs, &all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// historyKey returns the key of the prompt in the history store
+func (m Model) historyKey() string {
+	if m.HistoryKey == "" {
+		return DefaultHistoryKey
+	}
+	return m.HistoryKey
+}
+
+// loadHistory loads the history entries of the prompt
+func (m *Model) loadHistory() {
+	m.history = nil
+	m.historyIndex = 0
+	if m.History == nil {
+		return
+	}
+	m.history, m.historyErr = m.History.Load(m.historyKey())
+	m.historyIndex = len(m.history)
+}
+
+// saveHistory adds the confirmed input to the history, the hidden
+// input and the blank input are never saved
+func (m *Model) saveHistory() {
+	value := m.input.Value()
+	if m.History == nil || m.EchoMode != EchoNormal || strings.TrimSpace(value) == "" {
+		return
+	}
+	m.historyErr = m.History.Add(m.historyKey(), value)
+}
+
+// setInput replaces the input with the given value, and refreshes
+// the validation and the suggestions
+func (m *Model) setInput(value string) tea.Cmd {
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.showErr = false
+	return tea.Batch(m.validate(value), m.refreshSuggestions())
+}
+
+// browseHistory moves through the history entries, the step is -1 for the
+// older entry and 1 for the newer entry; the unfinished input is kept as the
+// newest entry
+func (m *Model) browseHistory(step int) tea.Cmd {
+	if m.EchoMode != EchoNormal || len(m.history) == 0 {
+		return nil
+	}
+	index := m.historyIndex + step
+	if index < 0 || index > len(m.history) {
+		return nil
+	}
+	if m.historyIndex == len(m.history) {
+		m.draft = m.input.Value()
+	}
+	m.historyIndex = index
+	if index == len(m.history) {
+		return m.setInput(m.draft)
+	}
+	return m.setInput(m.history[index])
+}
+
+// startSearch enters the reverse incremental search mode
+func (m *Model) startSearch() {
+	m.searching = true
+	m.searchQuery = ""
+	m.searchIndex = len(m.history)
+	m.searchFailed = false
+	m.searchOrigin = m.input.Value()
+}
+
+// search finds the newest entry containing the query before the given index
+func (m *Model) search(before int) {
+	for i := before - 1; i >= 0; i-- {
+		if strings.Contains(m.history[i], m.searchQuery) {
+			m.searchIndex = i
+			m.searchFailed = false
+			m.input.SetValue(m.history[i])
+			m.input.CursorEnd()
+			return
+		}
+	}
+	m.searchFailed = true
+}
+
+// updateSearch handles the keys in the search mode, the search ends with
+// the matched entry when any key other than the search keys is pressed
+func (m *Model) updateSearch(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	switch {
+	case common.Matches(msg, m.KeyMap.CancelSearch):
+		m.searching = false
+		return true, m.setInput(m.searchOrigin)
+	case common.Matches(msg, m.KeyMap.Search):
+		if m.searchQuery != "" {
+			m.search(m.searchIndex)
+		}
+		return true, nil
+	case msg.Type == tea.KeyBackspace:
+		if q := []rune(m.searchQuery); len(q) > 0 {
+			m.searchQuery = string(q[:len(q)-1])
+			m.search(len(m.history))
+		}
+		return true, nil
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+		m.searchQuery += string(msg.Runes)
+		if msg.Type == tea.KeySpace {
+			m.searchQuery += " "
+		}
+		// the current match is kept if it still contains the query
+		before := m.searchIndex + 1
+		if before > len(m.history) {
+			before = len(m.history)
+		}
+		m.search(before)
+		return true, nil
+	}
+
+	// the confirm key only accepts the matched entry
+	m.searching = false
+	cmd = m.setInput(m.input.Value())
+	return common.Matches(msg, m.KeyMap.Confirm), cmd
+}
+
+// searchView renders the search line under the input
+func (m Model) searchView() string {
+	if !m.searching {
+		return ""
+	}
+	label := "reverse-i-search"
+	if m.searchFailed {
+		label = "failed " + label
+	}
+	return m.render(styleGhost, "("+label+")`"+m.searchQuery+"'") + "\n"
+}
+
+// HistoryError returns the error of loading or saving the history
+func (m Model) HistoryError() error {
+	return m.historyErr
+}
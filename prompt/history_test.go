@@ -0,0 +1,153 @@
+package prompt
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize int
+		adds    [][2]string
+		want    map[string][]string
+	}{
+		{
+			name: "append",
+			adds: [][2]string{{"k", "a"}, {"k", "b"}},
+			want: map[string][]string{"k": {"a", "b"}},
+		},
+		{
+			name: "duplicate moved to the end",
+			adds: [][2]string{{"k", "a"}, {"k", "b"}, {"k", "a"}},
+			want: map[string][]string{"k": {"b", "a"}},
+		},
+		{
+			name:    "oldest dropped beyond the max size",
+			maxSize: 2,
+			adds:    [][2]string{{"k", "a"}, {"k", "b"}, {"k", "c"}},
+			want:    map[string][]string{"k": {"b", "c"}},
+		},
+		{
+			name:    "max size per key",
+			maxSize: 2,
+			adds:    [][2]string{{"x", "a"}, {"y", "a"}, {"x", "b"}, {"x", "c"}, {"y", "b"}},
+			want:    map[string][]string{"x": {"b", "c"}, "y": {"a", "b"}},
+		},
+		{
+			name: "keys separated",
+			adds: [][2]string{{"x", "a"}, {"y", "b"}, {"x", "b"}},
+			want: map[string][]string{"x": {"a", "b"}, "y": {"b"}, "z": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sub", "history.json")
+			h := NewFileHistory(path, tt.maxSize)
+			for _, add := range tt.adds {
+				if err := h.Add(add[0], add[1]); err != nil {
+					t.Fatalf("Add(%q, %q): %v", add[0], add[1], err)
+				}
+			}
+			// a new store reads the entries back from the file
+			reopened := NewFileHistory(path, tt.maxSize)
+			for key, want := range tt.want {
+				got, err := reopened.Load(key)
+				if err != nil {
+					t.Fatalf("Load(%q): %v", key, err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Load(%q) = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFileHistoryEmptyFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name  string
+		setup func(path string) error
+	}{
+		{"missing", func(string) error { return nil }},
+		{"empty", func(path string) error { return os.WriteFile(path, nil, 0600) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name)
+			if err := tt.setup(path); err != nil {
+				t.Fatal(err)
+			}
+			h := NewFileHistory(path, 0)
+			if entries, err := h.Load("k"); err != nil || len(entries) != 0 {
+				t.Errorf("Load() = %q, %v, want no entries", entries, err)
+			}
+			if err := h.Add("k", "a"); err != nil {
+				t.Fatalf("Add(): %v", err)
+			}
+			if entries, err := h.Load("k"); err != nil || !reflect.DeepEqual(entries, []string{"a"}) {
+				t.Errorf("Load() = %q, %v, want [a]", entries, err)
+			}
+		})
+	}
+}
+
+func TestFileHistoryAtomicReplace(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.json")
+	h := NewFileHistory(path, 0)
+	if err := h.Add("k", "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the file opened before Add keeps the old content, it is replaced by
+	// renaming a new file instead of being rewritten in place
+	old, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer old.Close()
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = h.Add("k", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(old); err != nil || string(got) != string(before) {
+		t.Errorf("the old file changed to %q, %v", got, err)
+	}
+
+	// the temporary files are removed
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != "history.json" {
+		t.Errorf("files left in the directory: %v", files)
+	}
+}
+
+func TestFileHistoryCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	h := NewFileHistory(path, 0)
+	if _, err := h.Load("k"); err == nil {
+		t.Error("Load() of the corrupted file returns no error")
+	}
+	// the corrupted file is not overwritten
+	if err := h.Add("k", "a"); err == nil {
+		t.Error("Add() to the corrupted file returns no error")
+	}
+	if bs, _ := os.ReadFile(path); string(bs) != "{" {
+		t.Errorf("the corrupted file changed to %q", bs)
+	}
+}
@@ -0,0 +1,103 @@
+package prompt
+
+import (
+	"github.com/mritd/bubbles/common"
+)
+
+// KeyMap defines the key bindings intercepted by the prompt, the other
+// keys are passed to the underlying textinput
+type KeyMap struct {
+	// Confirm finishes the input if there is no verification error
+	Confirm common.Binding
+	// Cancel cancels the input
+	Cancel common.Binding
+	// Help switches between the short and the expanded help view, it is
+	// bound to ctrl+/ by default because the printable keys are input
+	Help common.Binding
+	// Next and Prev cycle the suggestions, a single suggestion is accepted directly
+	Next common.Binding
+	Prev common.Binding
+	// Accept replaces the input with the highlighted or the top suggestion,
+	// it is only intercepted when the cursor is at the end of the input
+	Accept common.Binding
+	// HistoryPrev and HistoryNext browse the older and the newer history entries
+	HistoryPrev common.Binding
+	HistoryNext common.Binding
+	// Search starts the reverse incremental search over the history, pressing it
+	// again finds the next older match
+	Search common.Binding
+	// CancelSearch ends the search and restores the input before searching
+	CancelSearch common.Binding
+}
+
+// DefaultKeyMap return the default key bindings of the prompt
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Confirm: common.NewBinding(common.WithKeys("enter"), common.WithHelp("enter", "confirm")),
+		Cancel:  common.NewBinding(common.WithKeys("ctrl+c"), common.WithHelp("ctrl+c", "quit")),
+		Help:    common.NewBinding(common.WithKeys("ctrl+_"), common.WithHelp("ctrl+/", "toggle help")),
+		Next:    common.NewBinding(common.WithKeys("tab"), common.WithHelp("tab", "next suggestion")),
+		Prev:    common.NewBinding(common.WithKeys("shift+tab"), common.WithHelp("shift+tab", "prev suggestion")),
+		Accept:  common.NewBinding(common.WithKeys("right"), common.WithHelp("→", "accept suggestion")),
+
+		HistoryPrev:  common.NewBinding(common.WithKeys("up"), common.WithHelp("↑", "older history")),
+		HistoryNext:  common.NewBinding(common.WithKeys("down"), common.WithHelp("↓", "newer history")),
+		Search:       common.NewBinding(common.WithKeys("ctrl+r"), common.WithHelp("ctrl+r", "search history")),
+		CancelSearch: common.NewBinding(common.WithKeys("esc", "ctrl+g"), common.WithHelp("esc", "cancel search")),
+	}
+}
+
+// VimKeyMap return the key bindings following the vim conventions,
+// esc cancels the input and ctrl+g cancels the history search
+func VimKeyMap() KeyMap {
+	km := DefaultKeyMap()
+	km.Cancel = common.NewBinding(common.WithKeys("esc", "ctrl+c"), common.WithHelp("esc", "quit"))
+	km.CancelSearch = common.NewBinding(common.WithKeys("ctrl+g"), common.WithHelp("ctrl+g", "cancel search"))
+	return km
+}
+
+// EmacsKeyMap return the key bindings following the emacs conventions,
+// ctrl+g cancels the input, ctrl+j confirms it and esc cancels the history search
+func EmacsKeyMap() KeyMap {
+	km := DefaultKeyMap()
+	km.Confirm = common.NewBinding(common.WithKeys("enter", "ctrl+j"), common.WithHelp("enter", "confirm"))
+	km.Cancel = common.NewBinding(common.WithKeys("ctrl+g", "ctrl+c"), common.WithHelp("C-g", "quit"))
+	km.Accept = common.NewBinding(common.WithKeys("right", "ctrl+f"), common.WithHelp("C-f", "accept suggestion"))
+	km.HistoryPrev = common.NewBinding(common.WithKeys("up", "ctrl+p"), common.WithHelp("C-p", "older history"))
+	km.HistoryNext = common.NewBinding(common.WithKeys("down", "ctrl+n"), common.WithHelp("C-n", "newer history"))
+	km.Search = common.NewBinding(common.WithKeys("ctrl+r"), common.WithHelp("C-r", "search history"))
+	km.CancelSearch = common.NewBinding(common.WithKeys("esc"), common.WithHelp("esc", "cancel search"))
+	return km
+}
+
+// ShortHelp return the bindings displayed in the short help view
+func (m Model) ShortHelp() []common.Binding {
+	if !m.completable() {
+		return []common.Binding{m.KeyMap.Confirm, m.KeyMap.Cancel, m.KeyMap.Help}
+	}
+	return []common.Binding{m.KeyMap.Confirm, m.KeyMap.Next, m.KeyMap.Accept, m.KeyMap.Cancel, m.KeyMap.Help}
+}
+
+// FullHelp return the bindings displayed in the expanded help view
+func (m Model) FullHelp() [][]common.Binding {
+	groups := [][]common.Binding{{m.KeyMap.Confirm, m.KeyMap.Cancel}}
+	if m.completable() {
+		groups = append(groups, []common.Binding{m.KeyMap.Next, m.KeyMap.Prev, m.KeyMap.Accept})
+	}
+	if m.History != nil && m.EchoMode == EchoNormal {
+		groups = append(groups, []common.Binding{m.KeyMap.HistoryPrev, m.KeyMap.HistoryNext, m.KeyMap.Search})
+	}
+	if m.searching {
+		groups = append(groups, []common.Binding{m.KeyMap.CancelSearch})
+	}
+	return append(groups, []common.Binding{m.KeyMap.Help})
+}
+
+// HelpView renders the help view of the active key bindings, the Help
+// binding switches between the short and the expanded view
+func (m Model) HelpView() string {
+	if m.KeyMap == nil {
+		return ""
+	}
+	return m.help.View(m)
+}
@@ -0,0 +1,78 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mritd/bubbles/common"
+)
+
+// Run runs the prompt until the input finishes: in a bubbletea program on a
+// terminal, or by RunPlain with stdin and stdout in the plain-text mode(see
+// common.IsPlain); Canceled and Value report the result
+func (m *Model) Run() error {
+	return common.RunProgram(func(msg tea.Msg) tea.Cmd {
+		_, cmd := m.Update(msg)
+		return cmd
+	}, func() string { return m.View() }, m.RunPlain)
+}
+
+// RunPlain runs the prompt in the plain-text mode(see common.IsPlain), the
+// answer is read from the next line of in, and the prompt is written to out
+// without ANSI escape sequences; an invalid answer is not asked again, the
+// error of the ValidateFunc is returned instead; an empty answer accepts the
+// Default value, or the InitialValue if there is no Default
+func (m *Model) RunPlain(in io.Reader, out io.Writer) error {
+	if !m.init {
+		m.initData()
+	}
+
+	fallback := m.Default
+	if fallback == "" {
+		fallback = m.InitialValue
+	}
+	prompt := common.StripANSI(m.Prompt)
+	hint := ""
+	if fallback != "" && m.EchoMode == EchoNormal {
+		hint = "[" + fallback + "] "
+	}
+	if _, err := io.WriteString(out, prompt+hint); err != nil {
+		return err
+	}
+	value, err := common.ReadLine(in)
+	if err != nil {
+		_, _ = io.WriteString(out, "\n")
+		return fmt.Errorf("%s%w", prompt, err)
+	}
+	if value == "" {
+		value = fallback
+	}
+
+	// the input is not echoed by a pipe, so the answer is written like the finished view
+	switch m.EchoMode {
+	case EchoNormal:
+		_, err = io.WriteString(out, value+"\n")
+	case EchoPassword:
+		_, err = io.WriteString(out, common.GenMask(len([]rune(value)))+"\n")
+	default:
+		_, err = io.WriteString(out, "\n")
+	}
+	if err != nil {
+		return err
+	}
+
+	m.input.SetValue(value)
+	m.err = m.ValidateFunc(value)
+	if m.err == nil && m.AsyncValidateFunc != nil {
+		m.err = m.AsyncValidateFunc(context.Background(), value)
+	}
+	if m.err != nil {
+		m.showErr = true
+		return fmt.Errorf("%sinvalid input: %w", prompt, m.err)
+	}
+	m.finished = true
+	m.saveHistory()
+	return nil
+}
@@ -1,13 +1,16 @@
 package prompt
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/mritd/bubbles/common"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
 )
 
 const (
@@ -15,9 +18,8 @@ const (
 	DefaultValidateOkPrefix  = "✔"
 	DefaultValidateErrPrefix = "✘"
 
-	ColorPrompt      = "2"
-	colorValidateOk  = "2"
-	colorValidateErr = "1"
+	// Deprecated: use Theme.Prompt instead
+	ColorPrompt = "2"
 )
 
 // EchoMode sets the input behavior of the text input field.
@@ -52,7 +54,7 @@ type Model struct {
 	Width int
 
 	// Prompt is the prefix of the prompt library, the user needs to define
-	// the format(including spaces)
+	// the format(including spaces); it is rendered with Styles.Prompt if Styles is set
 	Prompt string
 
 	// ValidateFunc is a "real-time verification" function, which verifies
@@ -65,14 +67,106 @@ type Model struct {
 	// ValidateErrPrefix is the prompt prefix when the verification is successful
 	ValidateErrPrefix string
 
+	// AsyncValidateFunc is the verification function for the slow checks(e.g. an API
+	// call), it runs in the background after the input passes the ValidateFunc and
+	// stops changing for ValidateDebounce; the context is cancelled when the input
+	// changes again, and the confirmation waits for its result
+	AsyncValidateFunc func(ctx context.Context, input string) error
+
+	// ValidateDebounce is the delay before the AsyncValidateFunc starts, defaults
+	// to DefaultValidateDebounce
+	ValidateDebounce time.Duration
+
+	// ValidatePendingPrefix is the prompt prefix while the AsyncValidateFunc is running
+	ValidatePendingPrefix string
+
 	// EchoMode sets the input behavior of the text input field.
 	EchoMode EchoMode
 
+	// InitialValue pre-fills the input, it is editable and the cursor is at the end
+	InitialValue string
+
+	// Placeholder is the dimmed hint displayed when the input is empty, defaults
+	// to the Default value
+	Placeholder string
+
+	// Default is the value accepted when the input is submitted empty
+	Default string
+
+	// KeyMap is the key bindings intercepted by the prompt, defaults to
+	// DefaultKeyMap()
+	KeyMap *KeyMap
+
+	// ShowHelp displays the help view of the key bindings under the input
+	ShowHelp bool
+
+	// Theme the colors of the prompt, defaults to common.DefaultTheme()
+	Theme *common.Theme
+
+	// ColorProfile overrides the color profile of the prompt, defaults to
+	// common.ColorProfile()
+	ColorProfile *termenv.Profile
+
+	// Styles the lipgloss styles of the prompt elements, if nil the elements
+	// are rendered with the Theme colors; DefaultStyles returns the equivalent styles
+	Styles *Styles
+
+	// Completions is the static candidate set of the completion, the candidates
+	// matching the input are listed under the input
+	Completions []string
+
+	// CompleteFunc returns the suggestions for the input, it replaces Completions
+	// and is called asynchronously after each change of the input
+	CompleteFunc func(input string) []string
+
+	// MaxSuggestions is the number of the suggestions displayed under the input,
+	// defaults to DefaultMaxSuggestions
+	MaxSuggestions int
+
+	// History stores the confirmed inputs, they can be browsed with up/down and
+	// searched with ctrl+r; the hidden input is never stored
+	History HistoryStore
+
+	// HistoryKey is the id of the prompt in the History, the prompts asking
+	// for the same kind of input should share it, defaults to DefaultHistoryKey
+	HistoryKey string
+
 	init     bool
 	canceled bool
 	finished bool
 	showErr  bool
 	err      error
+	help     common.Help
+
+	// id identifies the suggestions returned asynchronously
+	id int
+	// suggestions are the suggestions for the current input
+	suggestions []string
+	// selected is the index of the highlighted suggestion, -1 if none
+	selected int
+
+	// history is the history entries loaded at initialization, historyIndex is
+	// the index of the browsed entry, and draft is the unfinished input
+	history      []string
+	historyIndex int
+	historyErr   error
+	draft        string
+	// searching indicates the reverse incremental search mode, searchIndex
+	// is the index of the matched entry, searchOrigin is the input before searching
+	searching    bool
+	searchQuery  string
+	searchIndex  int
+	searchFailed bool
+	searchOrigin string
+
+	// pending indicates the asynchronous validation is running, confirming indicates
+	// the confirm key was pressed during it; validateSeq identifies the latest validation,
+	// validated indicates the input has been validated at least once
+	pending        bool
+	confirming     bool
+	validated      bool
+	validateSeq    int
+	cancelValidate context.CancelFunc
 
 	input textinput.Model
 }
@@ -89,9 +183,25 @@ func (m *Model) initData() {
 	if m.ValidateErrPrefix == "" {
 		m.ValidateErrPrefix = DefaultValidateErrPrefix
 	}
+	if m.ValidatePendingPrefix == "" {
+		m.ValidatePendingPrefix = DefaultValidatePendingPrefix
+	}
 	if m.Prompt == "" {
-		m.Prompt = common.FontColor(DefaultPrompt, ColorPrompt)
+		m.Prompt = m.render(stylePrompt, DefaultPrompt)
+	} else if m.Styles != nil {
+		m.Prompt = m.render(stylePrompt, m.Prompt)
+	}
+	if m.KeyMap == nil {
+		km := DefaultKeyMap()
+		m.KeyMap = &km
+	}
+	m.help.Theme = m.Theme
+	m.help.Profile = m.ColorProfile
+	if m.id == 0 {
+		m.id = nextID()
 	}
+	m.selected = -1
+	m.loadHistory()
 
 	in := textinput.NewModel()
 	in.CharLimit = m.CharLimit
@@ -99,6 +209,10 @@ func (m *Model) initData() {
 	in.Prompt = m.Prompt
 	in.EchoMode = textinput.EchoMode(m.EchoMode)
 	in.Focus()
+	if m.InitialValue != "" {
+		in.SetValue(m.InitialValue)
+		in.CursorEnd()
+	}
 
 	m.input = in
 	m.init = true
@@ -109,26 +223,31 @@ func (m Model) View() string {
 	if m.finished {
 		switch m.EchoMode {
 		case EchoNormal:
-			return common.FontColor(m.ValidateOkPrefix, colorValidateOk) + " " + m.Prompt + m.Value() + "\n"
+			return m.render(styleValidateOk, m.ValidateOkPrefix) + " " + m.Prompt + m.Value() + "\n"
 		case EchoNone:
-			return common.FontColor(m.ValidateOkPrefix, colorValidateOk) + " " + m.Prompt + "\n"
+			return m.render(styleValidateOk, m.ValidateOkPrefix) + " " + m.Prompt + "\n"
 		case EchoPassword:
-			return common.FontColor(m.ValidateOkPrefix, colorValidateOk) + " " + m.Prompt + common.GenMask(len([]rune(m.Value()))) + "\n"
+			return m.render(styleValidateOk, m.ValidateOkPrefix) + " " + m.Prompt + common.GenMask(len([]rune(m.Value()))) + "\n"
 		}
 	}
 
-	var prompt, errMsg string
-	if m.err != nil {
-		prompt = common.FontColor(m.ValidateErrPrefix, colorValidateErr) + " " + m.input.View()
+	var prompt, errMsg, help string
+	if m.ShowHelp {
+		help = m.HelpView() + "\n"
+	}
+	if m.pending {
+		prompt = m.render(styleValidatePending, m.ValidatePendingPrefix) + " " + m.input.View() + m.ghostView() + m.placeholderView()
+	} else if m.err != nil {
+		prompt = m.render(styleValidateErr, m.ValidateErrPrefix) + " " + m.input.View() + m.ghostView() + m.placeholderView()
 		if m.showErr {
-			errMsg = common.FontColor(fmt.Sprintf("%s ERROR: %s\n", m.ValidateErrPrefix, m.err.Error()), colorValidateErr)
-			return fmt.Sprintf("%s\n%s\n", prompt, errMsg)
+			errMsg = m.render(styleError, fmt.Sprintf("%s ERROR: %s", m.ValidateErrPrefix, m.err.Error())) + "\n"
+			return fmt.Sprintf("%s\n%s%s%s\n%s", prompt, m.searchView(), m.suggestionsView(), errMsg, help)
 		}
 	} else {
-		prompt = common.FontColor(m.ValidateOkPrefix, colorValidateOk) + " " + m.input.View()
+		prompt = m.render(styleValidateOk, m.ValidateOkPrefix) + " " + m.input.View() + m.ghostView() + m.placeholderView()
 	}
 
-	return prompt + "\n"
+	return prompt + "\n" + m.searchView() + m.suggestionsView() + help
 }
 
 // Update method responds to various events and modifies the data model
@@ -136,38 +255,98 @@ func (m Model) View() string {
 func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	if !m.init {
 		m.initData()
+		if m.InitialValue != "" {
+			return m, m.validate(m.InitialValue)
+		}
 		return m, nil
 	}
 
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// the search keys take precedence over the other keys while searching,
+		// e.g. CancelSearch only ends the search even if it is also bound to Cancel
+		if m.searching {
+			handled, cmd := m.updateSearch(msg)
+			if handled {
+				return m, cmd
+			}
+			// the key ending the search is processed as usual
+			_, next := m.Update(msg)
+			return m, tea.Batch(cmd, next)
+		}
+
 		// We intercept some key events, because we need to handle it in the upper layer
-		switch msg.Type {
-		case tea.KeyCtrlC:
-			// Terminate the UI program when Ctrl+C is pressed
+		switch {
+		case common.Matches(msg, m.KeyMap.Cancel):
+			// Terminate the UI program when the Cancel key is pressed
 			m.canceled = true
+			m.cancelValidation()
 			return m, tea.Quit
-		case tea.KeyEnter:
-			// If the real-time verification function does not return an error,
-			// then the input has been completed
-			if m.err == nil {
-				m.finished = true
-				return m, common.Done
+		case common.Matches(msg, m.KeyMap.Help):
+			m.help.ShowAll = !m.help.ShowAll
+			return m, nil
+		case m.History != nil && common.Matches(msg, m.KeyMap.HistoryPrev):
+			return m, m.browseHistory(-1)
+		case m.History != nil && common.Matches(msg, m.KeyMap.HistoryNext):
+			return m, m.browseHistory(1)
+		case m.History != nil && m.EchoMode == EchoNormal && common.Matches(msg, m.KeyMap.Search):
+			m.startSearch()
+			return m, nil
+		case m.completable() && common.Matches(msg, m.KeyMap.Next, m.KeyMap.Prev):
+			// a single suggestion is accepted directly, otherwise the suggestions are cycled
+			if len(m.suggestions) == 1 {
+				cmd, _ := m.acceptSuggestion()
+				return m, cmd
 			}
-
-			// If there is a verification error, the error message should be display
-			m.showErr = true
-		case tea.KeyRunes:
+			if common.Matches(msg, m.KeyMap.Next) {
+				m.cycleSuggestion(1)
+			} else {
+				m.cycleSuggestion(-1)
+			}
+			return m, nil
+		case m.completable() && common.Matches(msg, m.KeyMap.Accept) && m.input.Cursor() == len([]rune(m.input.Value())):
+			// the key moves the cursor as usual if it is not at the end or there is no suggestion
+			if cmd, ok := m.acceptSuggestion(); ok {
+				return m, cmd
+			}
+		case common.Matches(msg, m.KeyMap.Confirm):
+			// the highlighted suggestion or the default value of the empty
+			// input is accepted before confirming
+			if m.selected >= 0 {
+				cmd, _ = m.acceptSuggestion()
+			} else if m.input.Value() == "" && m.Default != "" {
+				cmd = m.setInput(m.Default)
+			}
+			return m, tea.Batch(cmd, m.confirm())
+		case msg.Type == tea.KeyRunes:
 			// Hide verification failure message when entering content again
 			m.showErr = false
-			m.err = nil
 		}
 
 		// Call the underlying textinput to update the terminal display
+		before := m.input.Value()
 		m.input, cmd = m.input.Update(msg)
 		// Perform real-time verification function after each input
-		m.err = m.ValidateFunc(m.input.Value())
+		if m.input.Value() != before {
+			cmd = tea.Batch(cmd, m.validate(m.input.Value()), m.refreshSuggestions())
+		} else if m.AsyncValidateFunc == nil {
+			m.err = m.ValidateFunc(m.input.Value())
+		}
+
+	case suggestionsMsg:
+		// the suggestions of an outdated input are dropped
+		if msg.id == m.id && msg.input == m.input.Value() {
+			m.suggestions = msg.items
+			m.selected = -1
+		}
+		return m, nil
+
+	case validateTickMsg:
+		return m, m.startValidation(msg)
+
+	case validatedMsg:
+		return m, m.finishValidation(msg)
 
 	// We handle errors just like any other message
 	// Note: msg is error only when there is an unexpected error in the underlying textinput
@@ -180,6 +359,21 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	return m, cmd
 }
 
+// placeholderView renders the placeholder after the cursor when the input is empty
+func (m Model) placeholderView() string {
+	if m.input.Value() != "" {
+		return ""
+	}
+	placeholder := m.Placeholder
+	if placeholder == "" && m.EchoMode == EchoNormal {
+		placeholder = m.Default
+	}
+	if placeholder == "" {
+		return ""
+	}
+	return m.render(stylePlaceholder, placeholder)
+}
+
 // Value return the input string
 func (m Model) Value() string {
 	return m.input.Value()
@@ -190,6 +384,12 @@ func (m Model) Canceled() bool {
 	return m.canceled
 }
 
+// fontColor sets the color of the given string and bolds the font with
+// the color profile of the prompt
+func (m Model) fontColor(str, color string) string {
+	return common.FontColorWithProfile(common.ProfileOrDefault(m.ColorProfile), str, color)
+}
+
 // VFDoNothing is a verification function that does nothing
 func VFDoNothing(_ string) error { return nil }
 
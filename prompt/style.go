@@ -0,0 +1,124 @@
+package prompt
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mritd/bubbles/common"
+)
+
+// Styles are the lipgloss styles of the prompt elements, they support the
+// borders, padding, background, underline and width alignment
+type Styles struct {
+	// Prompt the style of the prompt, it is also applied to the custom Prompt
+	Prompt lipgloss.Style
+	// ValidateOk the style of the prefix when the validation succeeds
+	ValidateOk lipgloss.Style
+	// ValidateErr the style of the prefix when the validation fails
+	ValidateErr lipgloss.Style
+	// ValidatePending the style of the prefix while the asynchronous validation is running
+	ValidatePending lipgloss.Style
+	// Error the style of the error line
+	Error lipgloss.Style
+	// Suggestion the style of the suggestions under the input
+	Suggestion lipgloss.Style
+	// SelectedSuggestion the style of the highlighted suggestion
+	SelectedSuggestion lipgloss.Style
+	// Ghost the style of the inline preview of the suggestion
+	Ghost lipgloss.Style
+	// Placeholder the style of the placeholder displayed when the input is empty
+	Placeholder lipgloss.Style
+}
+
+// DefaultStyles returns the styles equivalent to the default look of the
+// prompt with the given theme, it is a starting point for customization
+func DefaultStyles(theme common.Theme) Styles {
+	style := func(color string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true)
+	}
+	return Styles{
+		Prompt:      style(theme.Prompt),
+		ValidateOk:  style(theme.Success),
+		ValidateErr: style(theme.Error),
+		// the pending prefix is dimmed like the other transient hints
+		ValidatePending: style(theme.Subtle),
+		Error:           style(theme.Error),
+		// the suggestions are not bolded, so that they are distinguished from the input
+		Suggestion:         lipgloss.NewStyle().Foreground(lipgloss.Color(theme.UnSelected)),
+		SelectedSuggestion: style(theme.Selected),
+		Ghost:              lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Subtle)),
+		Placeholder:        lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Subtle)),
+	}
+}
+
+// styleKind identifies the element of the prompt rendered by a style
+type styleKind int
+
+const (
+	stylePrompt styleKind = iota
+	styleValidateOk
+	styleValidateErr
+	styleError
+	styleSuggestion
+	styleSuggestionSelected
+	styleGhost
+	stylePlaceholder
+	styleValidatePending
+)
+
+// render renders the string as the given element, it uses the lipgloss style
+// of Styles if set, otherwise the theme color, bold except for the dimmed elements
+func (m Model) render(kind styleKind, str string) string {
+	theme := m.CurrentTheme()
+	if m.Styles == nil {
+		color, bold := theme.Error, true
+		switch kind {
+		case stylePrompt:
+			color = theme.Prompt
+		case styleValidateOk:
+			color = theme.Success
+		case styleSuggestion:
+			color, bold = theme.UnSelected, false
+		case styleSuggestionSelected:
+			color = theme.Selected
+		case styleGhost, stylePlaceholder:
+			color, bold = theme.Subtle, false
+		case styleValidatePending:
+			color = theme.Subtle
+		}
+		if !bold {
+			return m.colorFg(str, color)
+		}
+		return m.fontColor(str, color)
+	}
+
+	style := m.Styles.Error
+	switch kind {
+	case stylePrompt:
+		style = m.Styles.Prompt
+	case styleValidateOk:
+		style = m.Styles.ValidateOk
+	case styleValidateErr:
+		style = m.Styles.ValidateErr
+	case styleSuggestion:
+		style = m.Styles.Suggestion
+	case styleSuggestionSelected:
+		style = m.Styles.SelectedSuggestion
+	case styleGhost:
+		style = m.Styles.Ghost
+	case stylePlaceholder:
+		style = m.Styles.Placeholder
+	case styleValidatePending:
+		style = m.Styles.ValidatePending
+	}
+	return common.RenderStyle(common.ProfileOrDefault(m.ColorProfile), style, str)
+}
+
+// colorFg sets the color of the given string without bolding the font
+func (m Model) colorFg(str, color string) string {
+	return common.ColorWithProfile(common.ProfileOrDefault(m.ColorProfile), str, color)
+}
+
+// CurrentTheme return the theme used by the prompt, it is the default theme
+// if Theme is not set
+func (m Model) CurrentTheme() common.Theme {
+	return common.ThemeOrDefault(m.Theme)
+}
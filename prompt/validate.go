@@ -0,0 +1,122 @@
+package prompt
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mritd/bubbles/common"
+)
+
+const (
+	// DefaultValidateDebounce is the default delay between the last change of
+	// the input and the start of the AsyncValidateFunc
+	DefaultValidateDebounce = 300 * time.Millisecond
+	// DefaultValidatePendingPrefix is the prompt prefix while the AsyncValidateFunc is running
+	DefaultValidatePendingPrefix = "…"
+)
+
+// validateTickMsg starts the asynchronous validation after the debounce
+type validateTickMsg struct {
+	id  int
+	seq int
+}
+
+// validatedMsg is the result of the asynchronous validation
+type validatedMsg struct {
+	id  int
+	seq int
+	err error
+}
+
+// validate runs the ValidateFunc on the input, and schedules the AsyncValidateFunc
+// after the debounce if the input passes it; the running validation is cancelled
+func (m *Model) validate(value string) tea.Cmd {
+	m.cancelValidation()
+	m.confirming = false
+	m.validated = true
+	m.err = m.ValidateFunc(value)
+	if m.AsyncValidateFunc == nil || m.err != nil {
+		return nil
+	}
+
+	m.pending = true
+	debounce := m.ValidateDebounce
+	if debounce <= 0 {
+		debounce = DefaultValidateDebounce
+	}
+	id, seq := m.id, m.validateSeq
+	return tea.Tick(debounce, func(time.Time) tea.Msg {
+		return validateTickMsg{id: id, seq: seq}
+	})
+}
+
+// cancelValidation cancels the context of the running validation, and
+// makes its result outdated
+func (m *Model) cancelValidation() {
+	m.validateSeq++
+	m.pending = false
+	if m.cancelValidate != nil {
+		m.cancelValidate()
+		m.cancelValidate = nil
+	}
+}
+
+// startValidation runs the AsyncValidateFunc in a command, the validation of
+// an outdated input is not started
+func (m *Model) startValidation(msg validateTickMsg) tea.Cmd {
+	if msg.id != m.id || msg.seq != m.validateSeq || !m.pending {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelValidate = cancel
+	fn, value := m.AsyncValidateFunc, m.input.Value()
+	return func() tea.Msg {
+		return validatedMsg{id: msg.id, seq: msg.seq, err: fn(ctx, value)}
+	}
+}
+
+// finishValidation records the result of the AsyncValidateFunc, the input is
+// confirmed if the confirm key was pressed while the validation was pending
+func (m *Model) finishValidation(msg validatedMsg) tea.Cmd {
+	if msg.id != m.id || msg.seq != m.validateSeq || !m.pending {
+		return nil
+	}
+	m.cancelValidation()
+	m.err = msg.err
+	if m.confirming {
+		m.confirming = false
+		return m.confirm()
+	}
+	return nil
+}
+
+// confirm finishes the input if it passes the validation, the confirmation
+// waits for the result if the asynchronous validation is pending; the input
+// which has never been validated(e.g. the untouched empty input) is validated first
+func (m *Model) confirm() tea.Cmd {
+	var cmd tea.Cmd
+	if !m.validated {
+		cmd = m.validate(m.input.Value())
+	}
+	if m.pending {
+		m.confirming = true
+		return cmd
+	}
+	// If the real-time verification function does not return an error,
+	// then the input has been completed
+	if m.err == nil {
+		m.finished = true
+		m.saveHistory()
+		return common.Done
+	}
+
+	// If there is a verification error, the error message should be display
+	m.showErr = true
+	return nil
+}
+
+// Pending returns whether the asynchronous validation of the input is pending
+func (m Model) Pending() bool {
+	return m.pending
+}
@@ -0,0 +1,65 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var errInvalid = errors.New("invalid")
+
+func TestConfirmUntouchedInput(t *testing.T) {
+	tests := []struct {
+		name         string
+		model        *Model
+		wantFinished bool
+	}{
+		{"sync invalid", &Model{ValidateFunc: func(string) error { return errInvalid }}, false},
+		{"sync valid", &Model{ValidateFunc: VFDoNothing}, true},
+		{"blank", &Model{ValidateFunc: VFNotBlank}, false},
+		{"default", &Model{ValidateFunc: VFNotBlank, Default: "a"}, true},
+		{"async invalid", &Model{
+			ValidateDebounce:  time.Millisecond,
+			AsyncValidateFunc: func(context.Context, string) error { return errInvalid },
+		}, false},
+		{"async valid", &Model{
+			ValidateDebounce:  time.Millisecond,
+			AsyncValidateFunc: func(context.Context, string) error { return nil },
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.model
+			m.Update(nil)
+			_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			// the asynchronous validation runs through its messages
+			for cmds := []tea.Cmd{cmd}; m.Pending() && len(cmds) > 0; {
+				cmd, cmds = cmds[0], cmds[1:]
+				if cmd == nil {
+					continue
+				}
+				msg := cmd()
+				// tea.Batch returns an unexported slice of commands
+				if v := reflect.ValueOf(msg); v.Kind() == reflect.Slice {
+					for i := 0; i < v.Len(); i++ {
+						cmds = append(cmds, v.Index(i).Interface().(tea.Cmd))
+					}
+					continue
+				}
+				_, cmd = m.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+			if m.finished != tt.wantFinished {
+				t.Errorf("finished = %v, want %v (err %v)", m.finished, tt.wantFinished, m.err)
+			}
+			if !tt.wantFinished && !m.showErr {
+				t.Error("the validation error is not displayed")
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package prompt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// semverRegexp is the regular expression suggested by https://semver.org,
+// the optional "v" prefix is allowed
+var semverRegexp = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// hostnameLabelRegexp matches a hostname label(RFC 1123)
+var hostnameLabelRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// VFMinLength returns a verification function that checks whether the input
+// has at least n characters
+func VFMinLength(n int) func(string) error {
+	return func(s string) error {
+		if len([]rune(s)) < n {
+			return fmt.Errorf("input must be at least %d characters", n)
+		}
+		return nil
+	}
+}
+
+// VFMaxLength returns a verification function that checks whether the input
+// has at most n characters
+func VFMaxLength(n int) func(string) error {
+	return func(s string) error {
+		if len([]rune(s)) > n {
+			return fmt.Errorf("input must be at most %d characters", n)
+		}
+		return nil
+	}
+}
+
+// VFRegexp returns a verification function that checks whether the input
+// matches the regular expression
+func VFRegexp(re *regexp.Regexp) func(string) error {
+	return func(s string) error {
+		if !re.MatchString(s) {
+			return fmt.Errorf("input does not match %s", re)
+		}
+		return nil
+	}
+}
+
+// VFInt is a verification function that checks whether the input is an integer
+func VFInt(s string) error {
+	if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+		return errors.New("input is not an integer")
+	}
+	return nil
+}
+
+// VFIntRange returns a verification function that checks whether the input
+// is an integer in [min, max]
+func VFIntRange(min, max int64) func(string) error {
+	return func(s string) error {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return errors.New("input is not an integer")
+		}
+		if n < min || n > max {
+			return fmt.Errorf("input must be between %d and %d", min, max)
+		}
+		return nil
+	}
+}
+
+// VFFloat is a verification function that checks whether the input is a number
+func VFFloat(s string) error {
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return errors.New("input is not a number")
+	}
+	return nil
+}
+
+// VFFloatRange returns a verification function that checks whether the input
+// is a number in [min, max]
+func VFFloatRange(min, max float64) func(string) error {
+	return func(s string) error {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return errors.New("input is not a number")
+		}
+		if f < min || f > max {
+			return fmt.Errorf("input must be between %g and %g", min, max)
+		}
+		return nil
+	}
+}
+
+// VFEmail is a verification function that checks whether the input is an
+// email address, the address with a display name is not accepted
+func VFEmail(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return errors.New("input is not a valid email address")
+	}
+	return nil
+}
+
+// VFURL is a verification function that checks whether the input is an
+// absolute URL with a scheme and a host
+func VFURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return errors.New("input is not a valid URL")
+	}
+	return nil
+}
+
+// VFHostname is a verification function that checks whether the input is
+// a hostname(RFC 1123), the trailing dot is allowed
+func VFHostname(s string) error {
+	host := strings.TrimSuffix(s, ".")
+	if host == "" || len(host) > 253 {
+		return errors.New("input is not a valid hostname")
+	}
+	for _, label := range strings.Split(host, ".") {
+		if !hostnameLabelRegexp.MatchString(label) {
+			return errors.New("input is not a valid hostname")
+		}
+	}
+	return nil
+}
+
+// VFIPv4 is a verification function that checks whether the input is an IPv4 address
+func VFIPv4(s string) error {
+	ip := net.ParseIP(s)
+	if ip == nil || ip.To4() == nil || strings.Contains(s, ":") {
+		return errors.New("input is not a valid IPv4 address")
+	}
+	return nil
+}
+
+// VFIPv6 is a verification function that checks whether the input is an IPv6 address
+func VFIPv6(s string) error {
+	if net.ParseIP(s) == nil || !strings.Contains(s, ":") {
+		return errors.New("input is not a valid IPv6 address")
+	}
+	return nil
+}
+
+// VFCIDR is a verification function that checks whether the input is a CIDR
+// notation of an IP network, e.g. 192.168.0.0/16 or 2001:db8::/32
+func VFCIDR(s string) error {
+	if _, _, err := net.ParseCIDR(s); err != nil {
+		return errors.New("input is not a valid CIDR")
+	}
+	return nil
+}
+
+// VFPort is a verification function that checks whether the input is a port in
+// [1, 65535], the signs and the leading zeros are not accepted
+func VFPort(s string) error {
+	if s == "" || s[0] < '1' || s[0] > '9' {
+		return errors.New("input is not a valid port")
+	}
+	n, err := strconv.ParseUint(s, 10, 16)
+	if err != nil || n < 1 {
+		return errors.New("input is not a valid port")
+	}
+	return nil
+}
+
+// VFFileExists is a verification function that checks whether the input is
+// the path of an existing file which is not a directory, the error of os.Stat
+// other than not existing(e.g. permission denied) is wrapped in the error
+func VFFileExists(s string) error {
+	info, err := os.Stat(s)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("file %s does not exist", s)
+	}
+	if err != nil {
+		return fmt.Errorf("can not access file %s: %w", s, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", s)
+	}
+	return nil
+}
+
+// VFDirExists is a verification function that checks whether the input is
+// the path of an existing directory, the error of os.Stat other than not
+// existing(e.g. permission denied) is wrapped in the error
+func VFDirExists(s string) error {
+	info, err := os.Stat(s)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("directory %s does not exist", s)
+	}
+	if err != nil {
+		return fmt.Errorf("can not access directory %s: %w", s, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", s)
+	}
+	return nil
+}
+
+// VFSemver is a verification function that checks whether the input is a
+// semantic version(https://semver.org), e.g. 1.2.3 or v1.2.3-rc.1
+func VFSemver(s string) error {
+	if !semverRegexp.MatchString(s) {
+		return errors.New("input is not a valid semantic version")
+	}
+	return nil
+}
+
+// VFDuration is a verification function that checks whether the input is a
+// duration accepted by time.ParseDuration, e.g. 1h30m
+func VFDuration(s string) error {
+	if _, err := time.ParseDuration(s); err != nil {
+		return errors.New("input is not a valid duration")
+	}
+	return nil
+}
+
+// VFJSON is a verification function that checks whether the input is a valid JSON value
+func VFJSON(s string) error {
+	if !json.Valid([]byte(s)) {
+		return errors.New("input is not valid JSON")
+	}
+	return nil
+}
+
+// VFAll returns a verification function that passes if all the given functions
+// pass, the error of the first failed function is returned
+func VFAll(vfs ...func(string) error) func(string) error {
+	return func(s string) error {
+		for _, vf := range vfs {
+			if err := vf(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// VFAny returns a verification function that passes if any of the given
+// functions passes, the errors of all the functions are returned otherwise
+func VFAny(vfs ...func(string) error) func(string) error {
+	return func(s string) error {
+		if len(vfs) == 0 {
+			return nil
+		}
+		msgs := make([]string, 0, len(vfs))
+		for _, vf := range vfs {
+			err := vf(s)
+			if err == nil {
+				return nil
+			}
+			msgs = append(msgs, err.Error())
+		}
+		return errors.New(strings.Join(msgs, ", or "))
+	}
+}
+
+// VFNot returns a verification function that passes if the given function
+// fails, the msg is the error message when the given function passes
+func VFNot(vf func(string) error, msg string) func(string) error {
+	return func(s string) error {
+		if vf(s) == nil {
+			return errors.New(msg)
+		}
+		return nil
+	}
+}
+
+// VFWithMessage returns a verification function that replaces the error
+// message of the given function with msg
+func VFWithMessage(vf func(string) error, msg string) func(string) error {
+	return func(s string) error {
+		if vf(s) != nil {
+			return errors.New(msg)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+package prompt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		vf    func(string) error
+		valid []string
+		wrong []string
+	}{
+		{"VFNotBlank", VFNotBlank, []string{"a", " a "}, []string{"", "  \t"}},
+		{"VFMinLength", VFMinLength(3), []string{"abc", "中文字", "abcd"}, []string{"", "ab", "中文"}},
+		{"VFMaxLength", VFMaxLength(3), []string{"", "abc", "中文字"}, []string{"abcd", "中文字符"}},
+		{"VFRegexp", VFRegexp(regexp.MustCompile(`^[a-z]+$`)), []string{"abc"}, []string{"", "ab1", "ABC"}},
+		{"VFInt", VFInt, []string{"0", "-12", "+7", "9223372036854775807"}, []string{"", "1.5", "a", "9223372036854775808"}},
+		{"VFIntRange", VFIntRange(-5, 10), []string{"-5", "0", "10"}, []string{"-6", "11", "x"}},
+		{"VFFloat", VFFloat, []string{"0", "-1.5", "1e3"}, []string{"", "1,5", "a"}},
+		{"VFFloatRange", VFFloatRange(0, 1), []string{"0", "0.5", "1"}, []string{"-0.1", "1.01", "x"}},
+		{"VFEmail", VFEmail, []string{"a@example.com", "a.b+c@example.co"}, []string{"", "a", "a@", "Name <a@example.com>"}},
+		{"VFURL", VFURL, []string{"https://example.com", "http://localhost:8080/a?b=c"}, []string{"", "example.com", "/path", "http://"}},
+		{"VFHostname", VFHostname, []string{"localhost", "a-b.example.com", "example.com."}, []string{"", "-a.com", "a..b", "a_b.com", "."}},
+		{"VFIPv4", VFIPv4, []string{"127.0.0.1", "255.255.255.255"}, []string{"", "256.0.0.1", "1.2.3", "::ffff:1.2.3.4"}},
+		{"VFIPv6", VFIPv6, []string{"::1", "2001:db8::1", "::ffff:1.2.3.4"}, []string{"", "1.2.3.4", "2001:db8::g"}},
+		{"VFCIDR", VFCIDR, []string{"192.168.0.0/16", "2001:db8::/32"}, []string{"", "192.168.0.0", "10.0.0.0/33"}},
+		{"VFPort", VFPort, []string{"1", "80", "65535"}, []string{"", "0", "65536", "+80", "-80", "0080", "08", " 80", "8O"}},
+		{"VFFileExists", VFFileExists, []string{file}, []string{"", dir, filepath.Join(dir, "missing")}},
+		{"VFDirExists", VFDirExists, []string{dir}, []string{"", file, filepath.Join(dir, "missing")}},
+		{"VFSemver", VFSemver, []string{"1.2.3", "v0.1.0", "1.0.0-rc.1+build.5"}, []string{"", "1.2", "01.2.3", "1.2.3-"}},
+		{"VFDuration", VFDuration, []string{"1h30m", "0", "-1.5s"}, []string{"", "1", "1d"}},
+		{"VFJSON", VFJSON, []string{`{"a":1}`, "[]", "null", `"s"`}, []string{"", "{", "{a:1}"}},
+		{"VFAll", VFAll(VFNotBlank, VFMaxLength(3)), []string{"a", "abc"}, []string{"", "abcd"}},
+		{"VFAll empty", VFAll(), []string{"", "a"}, nil},
+		{"VFAny", VFAny(VFIPv4, VFHostname), []string{"1.2.3.4", "example.com"}, []string{"", "a_b"}},
+		{"VFAny empty", VFAny(), []string{"", "a"}, nil},
+		{"VFNot", VFNot(VFInt, "input must not be a number"), []string{"a", ""}, []string{"1", "-2"}},
+		{"VFWithMessage", VFWithMessage(VFInt, "not a count"), []string{"1"}, []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, s := range tt.valid {
+				if err := tt.vf(s); err != nil {
+					t.Errorf("%q: unexpected error: %v", s, err)
+				}
+			}
+			for _, s := range tt.wrong {
+				if err := tt.vf(s); err == nil {
+					t.Errorf("%q: expected an error", s)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatorMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		vf   func(string) error
+		in   string
+		want string
+	}{
+		{"VFAny", VFAny(VFInt, VFIPv4), "a", "input is not an integer, or input is not a valid IPv4 address"},
+		{"VFAll", VFAll(VFNotBlank, VFInt), "", "input is empty"},
+		{"VFNot", VFNot(VFInt, "input must not be a number"), "1", "input must not be a number"},
+		{"VFWithMessage", VFWithMessage(VFInt, "not a count"), "a", "not a count"},
+		{"VFIntRange", VFIntRange(1, 3), "4", "input must be between 1 and 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.vf(tt.in)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExistsStatError(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	// a path under a regular file fails with ENOTDIR instead of not existing
+	path := filepath.Join(file, "child")
+
+	tests := []struct {
+		name string
+		vf   func(string) error
+	}{
+		{"VFFileExists", VFFileExists},
+		{"VFDirExists", VFDirExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.vf(path)
+			var pathErr *os.PathError
+			if !errors.As(err, &pathErr) {
+				t.Errorf("got %v, want the wrapped *os.PathError", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package selector
+
+import (
+	"github.com/mritd/bubbles/common"
+)
+
+// KeyMap defines the key bindings of the selector, the bindings can be
+// rebound or disabled through the methods of common.Binding
+type KeyMap struct {
+	// Up moves the cursor up
+	Up common.Binding
+	// Down moves the cursor down
+	Down common.Binding
+	// PrePage slides the page data area forward one page
+	PrePage common.Binding
+	// NextPage slides the page data area back one page
+	NextPage common.Binding
+	// Jump moves the cursor to the given line of the page, the keys must be digits
+	Jump common.Binding
+	// Confirm finishes the selection
+	Confirm common.Binding
+	// Cancel cancels the selection
+	Cancel common.Binding
+	// Toggle switches the checked state of the item under the cursor in the multi-selection mode
+	Toggle common.Binding
+	// CheckAll checks all items in the multi-selection mode
+	CheckAll common.Binding
+	// UnCheckAll unchecks all items in the multi-selection mode
+	UnCheckAll common.Binding
+	// ClearFilter clears the filter input in the filter mode
+	ClearFilter common.Binding
+	// Help switches between the short and the expanded help view, ctrl+/ is
+	// bound as well because "?" is used as filter input in the filter mode
+	Help common.Binding
+}
+
+// DefaultKeyMap return the default key bindings of the selector, the letter
+// keys are bound in both cases like the case-insensitive keys of the earlier versions
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:          common.NewBinding(common.WithKeys("up"), common.WithHelp("↑", "up")),
+		Down:        common.NewBinding(common.WithKeys("down"), common.WithHelp("↓", "down")),
+		PrePage:     common.NewBinding(common.WithKeys("left", "pgup", "h", "j", "H", "J"), common.WithHelp("←/pgup", "prev page")),
+		NextPage:    common.NewBinding(common.WithKeys("right", "pgdown", "l", "k", "L", "K"), common.WithHelp("→/pgdown", "next page")),
+		Jump:        common.NewBinding(common.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), common.WithHelp("1-9", "jump")),
+		Confirm:     common.NewBinding(common.WithKeys("enter"), common.WithHelp("enter", "confirm")),
+		Cancel:      common.NewBinding(common.WithKeys("q", "Q", "ctrl+c"), common.WithHelp("q", "quit")),
+		Toggle:      common.NewBinding(common.WithKeys(" "), common.WithHelp("space", "toggle")),
+		CheckAll:    common.NewBinding(common.WithKeys("a"), common.WithHelp("a", "check all")),
+		UnCheckAll:  common.NewBinding(common.WithKeys("A"), common.WithHelp("A", "uncheck all")),
+		ClearFilter: common.NewBinding(common.WithKeys("esc"), common.WithHelp("esc", "clear filter")),
+		Help:        common.NewBinding(common.WithKeys("?", "ctrl+_"), common.WithHelp("?", "toggle help")),
+	}
+}
+
+// VimKeyMap return the key bindings following the vim conventions,
+// j/k move down/up and h/l slide the page
+func VimKeyMap() KeyMap {
+	km := DefaultKeyMap()
+	km.Up = common.NewBinding(common.WithKeys("up", "k"), common.WithHelp("↑/k", "up"))
+	km.Down = common.NewBinding(common.WithKeys("down", "j"), common.WithHelp("↓/j", "down"))
+	km.PrePage = common.NewBinding(common.WithKeys("left", "pgup", "h", "ctrl+b"), common.WithHelp("←/h", "prev page"))
+	km.NextPage = common.NewBinding(common.WithKeys("right", "pgdown", "l", "ctrl+f"), common.WithHelp("→/l", "next page"))
+	return km
+}
+
+// EmacsKeyMap return the key bindings following the emacs conventions,
+// ctrl+n/ctrl+p move down/up and ctrl+v/alt+v slide the page
+func EmacsKeyMap() KeyMap {
+	km := DefaultKeyMap()
+	km.Up = common.NewBinding(common.WithKeys("up", "ctrl+p"), common.WithHelp("↑/C-p", "up"))
+	km.Down = common.NewBinding(common.WithKeys("down", "ctrl+n"), common.WithHelp("↓/C-n", "down"))
+	km.PrePage = common.NewBinding(common.WithKeys("left", "pgup", "alt+v"), common.WithHelp("←/M-v", "prev page"))
+	km.NextPage = common.NewBinding(common.WithKeys("right", "pgdown", "ctrl+v"), common.WithHelp("→/C-v", "next page"))
+	km.Cancel = common.NewBinding(common.WithKeys("ctrl+g", "ctrl+c"), common.WithHelp("C-g", "quit"))
+	km.CheckAll = common.NewBinding(common.WithKeys("alt+a"), common.WithHelp("M-a", "check all"))
+	km.UnCheckAll = common.NewBinding(common.WithKeys("alt+A"), common.WithHelp("M-A", "uncheck all"))
+	return km
+}
+
+// withoutRuneKeys return a copy of the key bindings without the single-character
+// keys, so that the printable characters can be used as filter input; space
+// is kept for toggling the item in the multi-selection mode
+func (k KeyMap) withoutRuneKeys() KeyMap {
+	k.Up = withoutRuneKeys(k.Up)
+	k.Down = withoutRuneKeys(k.Down)
+	k.PrePage = withoutRuneKeys(k.PrePage)
+	k.NextPage = withoutRuneKeys(k.NextPage)
+	k.Jump = withoutRuneKeys(k.Jump)
+	k.Confirm = withoutRuneKeys(k.Confirm)
+	k.Cancel = withoutRuneKeys(k.Cancel)
+	k.Toggle = withoutRuneKeys(k.Toggle, " ")
+	k.CheckAll = withoutRuneKeys(k.CheckAll)
+	k.UnCheckAll = withoutRuneKeys(k.UnCheckAll)
+	k.ClearFilter = withoutRuneKeys(k.ClearFilter)
+	k.Help = withoutRuneKeys(k.Help)
+	return k
+}
+
+// withoutRuneKeys removes the single-character keys from the binding, the
+// help key naming a removed key is replaced by the first remaining key
+func withoutRuneKeys(b common.Binding, keep ...string) common.Binding {
+	stripped := b.WithoutRuneKeys(keep...)
+	help := b.Help()
+	if !containsKey(b.Keys(), help.Key) || containsKey(stripped.Keys(), help.Key) || len(stripped.Keys()) == 0 {
+		return stripped
+	}
+	key := stripped.Keys()[0]
+	// ctrl+/ is received as ctrl+_ by the terminals
+	if key == "ctrl+_" {
+		key = "ctrl+/"
+	}
+	stripped.SetHelp(key, help.Desc)
+	return stripped
+}
+
+// containsKey determine whether the keys contain the given key
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ShortHelp return the bindings displayed in the short help view, the
+// bindings of the disabled modes are not displayed
+func (m Model) ShortHelp() []common.Binding {
+	bindings := []common.Binding{m.KeyMap.Up, m.KeyMap.Down, m.KeyMap.PrePage, m.KeyMap.NextPage, m.KeyMap.Jump}
+	if m.MultiSelect {
+		bindings = append(bindings, m.KeyMap.Toggle)
+	}
+	return append(bindings, m.KeyMap.Confirm, m.KeyMap.Cancel, m.KeyMap.Help)
+}
+
+// FullHelp return the bindings displayed in the expanded help view, the
+// bindings of the disabled modes are not displayed
+func (m Model) FullHelp() [][]common.Binding {
+	groups := [][]common.Binding{
+		{m.KeyMap.Up, m.KeyMap.Down, m.KeyMap.Jump},
+		{m.KeyMap.PrePage, m.KeyMap.NextPage},
+	}
+	if m.MultiSelect {
+		groups = append(groups, []common.Binding{m.KeyMap.Toggle, m.KeyMap.CheckAll, m.KeyMap.UnCheckAll})
+	}
+	if m.Filterable {
+		groups = append(groups, []common.Binding{m.KeyMap.ClearFilter})
+	}
+	return append(groups, []common.Binding{m.KeyMap.Confirm, m.KeyMap.Cancel, m.KeyMap.Help})
+}
+
+// HelpView renders the help view of the active key bindings, "?"(or ctrl+/) switches
+// between the short and the expanded view; it is designed to be used in
+// HeaderFunc or FooterFunc
+func (m Model) HelpView() string {
+	if m.KeyMap == nil {
+		return ""
+	}
+	return m.help.View(m)
+}
@@ -0,0 +1,116 @@
+package selector
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mritd/bubbles/common"
+)
+
+// Run runs the selector until the selection finishes: in a bubbletea program on
+// a terminal, or by RunPlain with stdin and stdout in the plain-text mode(see
+// common.IsPlain); Canceled, Selected and Choices report the result
+func (m *Model) Run() error {
+	return common.RunProgram(func(msg tea.Msg) tea.Cmd {
+		_, cmd := m.Update(msg)
+		return cmd
+	}, func() string { return m.View() }, m.RunPlain)
+}
+
+// RunPlain runs the selector in the plain-text mode(see common.IsPlain), the
+// items are rendered by UnSelectedFunc and prefixed with their serial numbers
+// unless the rendered line starts with it already(e.g. DefaultUnSelectedFuncWithIndex),
+// and the answer is read from the
+// next line of in: the serial number or the FilterFunc string of the item; in
+// the multi-selection mode the answer is a comma separated list, an empty line
+// checks nothing. An invalid answer is not asked again, an error is returned instead
+func (m *Model) RunPlain(in io.Reader, out io.Writer) error {
+	if !m.init {
+		m.initData()
+	}
+	if len(m.Data) == 0 {
+		return fmt.Errorf("no items to select")
+	}
+
+	var b strings.Builder
+	for i, obj := range m.Data {
+		line := strings.TrimSpace(common.StripANSI(m.UnSelectedFunc(*m, obj, i)))
+		if !numbered(line, i+1) {
+			line = fmt.Sprintf("%d) %s", i+1, line)
+		}
+		b.WriteString(line + "\n")
+	}
+	if m.MultiSelect {
+		b.WriteString(fmt.Sprintf("Select items (1-%d, comma separated): ", len(m.Data)))
+	} else {
+		b.WriteString(fmt.Sprintf("Select an item (1-%d): ", len(m.Data)))
+	}
+	if _, err := io.WriteString(out, b.String()); err != nil {
+		return err
+	}
+
+	answer, err := common.ReadLine(in)
+	if err != nil {
+		_, _ = io.WriteString(out, "\n")
+		return err
+	}
+	if _, err = io.WriteString(out, answer+"\n"); err != nil {
+		return err
+	}
+
+	if m.MultiSelect {
+		for _, s := range strings.Split(answer, ",") {
+			if strings.TrimSpace(s) == "" {
+				continue
+			}
+			idx, err := m.plainIndex(s)
+			if err != nil {
+				return err
+			}
+			m.checked[idx] = true
+		}
+	} else {
+		idx, err := m.plainIndex(answer)
+		if err != nil {
+			return err
+		}
+		// there is no filter in the plain-text mode, so the global index is the index of items
+		m.index = idx
+		m.pageIndex = 0
+	}
+	m.finished = true
+	return nil
+}
+
+// numbered determine whether the rendered line starts with the serial number,
+// the number may be wrapped in brackets, e.g. "2. fix" or "[2] fix"
+func numbered(line string, n int) bool {
+	line = strings.TrimLeft(line, "[(#")
+	num := strconv.Itoa(n)
+	if !strings.HasPrefix(line, num) {
+		return false
+	}
+	rest := line[len(num):]
+	return rest == "" || rest[0] < '0' || rest[0] > '9'
+}
+
+// plainIndex returns the global index of the item by its serial number
+// or its FilterFunc string
+func (m *Model) plainIndex(answer string) (int, error) {
+	answer = strings.TrimSpace(answer)
+	if n, err := strconv.Atoi(answer); err == nil {
+		if n < 1 || n > len(m.Data) {
+			return 0, fmt.Errorf("invalid selection %d: out of range 1-%d", n, len(m.Data))
+		}
+		return n - 1, nil
+	}
+	for i, obj := range m.Data {
+		if strings.EqualFold(m.FilterFunc(obj), answer) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid selection %q: no item matches", answer)
+}
@@ -4,36 +4,58 @@ package selector
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/mritd/bubbles/common"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
 )
 
 const (
-	DefaultHeader   = "Use the arrow keys to navigate: ↓ ↑ → ←"
-	DefaultFooter   = "Current page number details: %d/%d"
-	DefaultCursor   = "»"
-	DefaultFinished = "Current selected: %s\n"
-
+	// Deprecated: the default header is rendered from the KeyMap with DefaultHeaderFormat
+	DefaultHeader       = "Use the arrow keys to navigate: ↓ ↑ → ←"
+	DefaultHeaderFormat = "Use the keys to navigate: %s"
+	DefaultFooter       = "Current page number details: %d/%d"
+	DefaultCursor       = "»"
+	DefaultFinished     = "Current selected: %s\n"
+	DefaultChecked      = "[x]"
+	DefaultUnChecked    = "[ ]"
+	DefaultNoMatches    = "No matching items"
+
+	DefaultFilterPrompt = "Filter: "
+
+	// Deprecated: the Color* constants are the colors of common.DarkTheme,
+	// use the Theme of the Model instead
 	ColorHeader     = "15"
 	ColorFooter     = "15"
 	ColorCursor     = "2"
 	ColorFinished   = "2"
 	ColorSelected   = "14"
 	ColorUnSelected = "8"
+	ColorChecked    = "2"
+	ColorMatched    = "11"
 )
 
+// Choice is a checked item in the multi-selection mode
+type Choice struct {
+	// Index is the global index of the item in Data
+	Index int
+	// Value is the checked item
+	Value interface{}
+}
+
 // Model is a data container used to store TUI status information,
 // the ui rendering success style is as follows:
 //
 //	Use the arrow keys to navigate: ↓ ↑ → ←
-// 	Select Commit Type:
+//	Select Commit Type:
 //
-// 	» [1] feat (Introducing new features)
+//	» [1] feat (Introducing new features)
 //	   2. fix (Bug fix)
 //	   3. docs (Writing docs)
 //	   4. style (Improving structure/format of the code)
@@ -43,7 +65,8 @@ const (
 //	Type: feat
 //	Description: 新功能(Introducing new features)
 type Model struct {
-	// HeaderFunc Header rendering function
+	// HeaderFunc Header rendering function, it always receives a real item; when
+	// the filter matches nothing it is not called and the default header is displayed
 	HeaderFunc func(m Model, obj interface{}, gdIndex int) string
 	// Cursor cursor rendering style
 	Cursor string
@@ -53,14 +76,45 @@ type Model struct {
 	SelectedFunc func(m Model, obj interface{}, gdIndex int) string
 	// UnSelectedFunc unselected data rendering function
 	UnSelectedFunc func(m Model, obj interface{}, gdIndex int) string
-	// FooterFunc footer rendering function
+	// FooterFunc footer rendering function, it always receives a real item; when
+	// the filter matches nothing it is not called and the default footer is displayed
 	FooterFunc func(m Model, obj interface{}, gdIndex int) string
-	// FinishedFunc finished rendering function
+	// FinishedFunc finished rendering function, in the multi-selection mode
+	// it receives all checked items as a []Choice
 	FinishedFunc func(selected interface{}) string
 	// PerPage data count per page
 	PerPage int
 	// Data the data set to be rendered
 	Data []interface{}
+	// MultiSelect enables the multi-selection mode, space toggles the item under
+	// the cursor, "a" checks all items and "A" unchecks all items; the render
+	// functions can read the checked state through Model.Checked(gdIndex)
+	MultiSelect bool
+	// CheckedMark checked item prefix in the multi-selection mode
+	CheckedMark string
+	// UnCheckedMark unchecked item prefix in the multi-selection mode
+	UnCheckedMark string
+	// KeyMap the key bindings of the selector, defaults to DefaultKeyMap()
+	KeyMap *KeyMap
+	// Theme the colors of the selector, defaults to common.DefaultTheme()
+	Theme *common.Theme
+	// ColorProfile overrides the color profile of the selector, defaults
+	// to common.ColorProfile()
+	ColorProfile *termenv.Profile
+	// Styles the lipgloss styles of the selector elements, if nil the elements
+	// are rendered with the Theme colors; DefaultStyles returns the equivalent styles
+	Styles *Styles
+	// Filterable enables the filter mode, the typed characters narrow Data by
+	// fuzzy matching, backspace deletes a character and esc clears the filter;
+	// the keys not bound to any action are used as filter input, so the default
+	// key bindings drop the single-character keys(q, h, j, k, l, 1-9...) in this mode
+	Filterable bool
+	// FilterFunc returns the string used to match the filter for each item,
+	// it defaults to fmt.Sprint(obj); the default render functions highlight
+	// the matched runes if the string is a part of fmt.Sprint(obj)
+	FilterFunc func(obj interface{}) string
+	// FilterPrompt the prefix of the filter line
+	FilterPrompt string
 
 	// init indicates whether the data model has completed initialization
 	init bool
@@ -78,19 +132,44 @@ type Model struct {
 	pageIndex int
 	// pageMaxIndex current page max index
 	pageMaxIndex int
+	// checked global indexes of the checked items in the multi-selection mode
+	checked map[int]bool
+	// help the help view state of the key bindings
+	help common.Help
+	// filter the filter input in the filter mode
+	filter string
+	// items the filtered data set, it is the same as Data when the filter is empty
+	items []interface{}
+	// origins the global indexes in Data corresponding to items
+	origins []int
+	// perPage the actual data count per page of the filtered data set
+	perPage int
 }
 
 // View reads the data state of the data model for rendering
 func (m Model) View() string {
 	if m.finished {
+		if m.MultiSelect {
+			return m.FinishedFunc(m.Choices())
+		}
 		return m.FinishedFunc(m.Selected())
 	}
 
 	// the cursor only needs to be displayed correctly
-	cursor := common.FontColor(m.Cursor, m.CursorColor)
+	cursor := m.renderCursor()
 	// template functions may be displayed dynamically at the head, tail and data area
 	// of the list, and a dynamic index(globalDynamicIndex) needs to be added
 	var header, data, footer string
+	if m.Filterable {
+		data = m.Render(StyleHeader, m.FilterPrompt) + m.filter + "\n\n"
+	}
+	// the filter matches nothing, the custom header and footer functions can not
+	// be called without an item, so the default ones are displayed
+	if m.init && len(m.pageData) == 0 {
+		header = defaultHeaderFunc(m, nil, -1)
+		footer = defaultFooterFunc(m, nil, -1)
+		data += m.Render(StyleUnSelected, DefaultNoMatches) + "\n"
+	}
 	for i, obj := range m.pageData {
 		// cursor prefix (selected lines need to be displayed,
 		// non-selected lines need not be displayed)
@@ -127,7 +206,10 @@ func (m Model) View() string {
 		// in three cases, `m.index - m.pageIndex = n`, `n` is the distance between the global real-time
 		// index and the page real-time index. when traversing the page data area, think of the traversal
 		// index i as a real-time page index pageIndex, `i + n =` i corresponding global index
-		globalDynamicIndex := i + (m.index - m.pageIndex)
+		//
+		// in the filter mode, the index calculated above is the index of the filtered data set,
+		// it needs to be converted to the index of the global data area(Data)
+		globalDynamicIndex := m.origins[i+(m.index-m.pageIndex)]
 		// when traversing the data area, if the traversed index is equal to the current page index,
 		// the currently traversed data is the data selected in the list menu, otherwise it is unselected data
 		if i == m.pageIndex {
@@ -148,6 +230,14 @@ func (m Model) View() string {
 			cursorPrefix = common.GenSpaces(runewidth.StringWidth(m.Cursor) + 1)
 			dataLine = m.UnSelectedFunc(m, obj, globalDynamicIndex) + "\n"
 		}
+		// in the multi-selection mode, the check mark is displayed between the cursor and the data
+		if m.MultiSelect {
+			if m.checked[globalDynamicIndex] {
+				cursorPrefix += m.Render(StyleChecked, m.CheckedMark) + " "
+			} else {
+				cursorPrefix += m.Render(StyleUnSelected, m.UnCheckedMark) + " "
+			}
+		}
 		data += cursorPrefix + dataLine
 		header = m.HeaderFunc(m, obj, globalDynamicIndex)
 		footer = m.FooterFunc(m, obj, globalDynamicIndex)
@@ -166,23 +256,43 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch strings.ToLower(msg.String()) {
-		case "q", "ctrl+c":
+		switch {
+		case common.Matches(msg, m.KeyMap.Cancel):
 			m.canceled = true
 			return m, tea.Quit
-		case "enter":
+		case common.Matches(msg, m.KeyMap.Confirm):
+			// nothing can be selected when the filter matches nothing
+			if len(m.items) == 0 {
+				return m, nil
+			}
 			m.finished = true
 			return m, common.Done
-		case "down":
+		case common.Matches(msg, m.KeyMap.Down):
 			m.moveDown()
-		case "up":
+		case common.Matches(msg, m.KeyMap.Up):
 			m.moveUp()
-		case "right", "pgdown", "l", "k":
+		case common.Matches(msg, m.KeyMap.NextPage):
 			m.nextPage()
-		case "left", "pgup", "h", "j":
+		case common.Matches(msg, m.KeyMap.PrePage):
 			m.prePage()
-		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		case common.Matches(msg, m.KeyMap.Jump):
 			m.forward(msg.String())
+		case m.MultiSelect && common.Matches(msg, m.KeyMap.Toggle):
+			if len(m.items) > 0 {
+				m.toggle(m.origins[m.index])
+			}
+		case m.MultiSelect && common.Matches(msg, m.KeyMap.CheckAll):
+			m.checkAll()
+		case m.MultiSelect && common.Matches(msg, m.KeyMap.UnCheckAll):
+			m.uncheckAll()
+		case common.Matches(msg, m.KeyMap.Help):
+			m.help.ShowAll = !m.help.ShowAll
+		case m.Filterable && common.Matches(msg, m.KeyMap.ClearFilter):
+			m.filter = ""
+			m.applyFilter()
+		case m.Filterable:
+			// the keys not bound to any action are used as filter input
+			m.updateFilter(msg)
 		}
 	}
 	return m, nil
@@ -210,7 +320,7 @@ func (m *Model) moveDown() {
 			// global index increment
 			m.index++
 			// window slide down one data
-			m.pageData = m.Data[m.index+1-m.PerPage : m.index+1]
+			m.pageData = m.items[m.index+1-m.perPage : m.index+1]
 			return
 		}
 	}
@@ -236,7 +346,7 @@ func (m *Model) moveUp() {
 		// check whether the global index reaches the minimum before sliding
 		if m.index > 0 {
 			// window slide up one data
-			m.pageData = m.Data[m.index-1 : m.index-1+m.PerPage]
+			m.pageData = m.items[m.index-1 : m.index-1+m.perPage]
 			// global index decrement
 			m.index--
 			return
@@ -247,28 +357,28 @@ func (m *Model) moveUp() {
 // nextPage triggers the page-down action, and does not change
 // the real-time page index(pageIndex)
 func (m *Model) nextPage() {
-	// Get the start and end position of the page data area slice: m.Data[start:end]
+	// Get the start and end position of the page data area slice: m.items[start:end]
 	//
 	// note: the slice is closed left and opened right: `[start,end)`
 	//       assuming that the global data area has unlimited length,
 	//       end should always be the actual page `length+1`,
-	//       the maximum value of end should be equal to `len(m.Data)`
+	//       the maximum value of end should be equal to `len(m.items)`
 	//       under limited length
 	pageStart, pageEnd := m.pageIndexInfo()
 	// there are two cases when `end` does not reach the maximum value
-	if pageEnd < len(m.Data) {
+	if pageEnd < len(m.items) {
 		// the `end` value is at least one page length away from the global maximum index
-		if len(m.Data)-pageEnd >= m.PerPage {
+		if len(m.items)-pageEnd >= m.perPage {
 			// slide back one page in the page data area
-			m.pageData = m.Data[pageStart+m.PerPage : pageEnd+m.PerPage]
+			m.pageData = m.items[pageStart+m.perPage : pageEnd+m.perPage]
 			// Global real-time index increases by one page length
-			m.index += m.PerPage
+			m.index += m.perPage
 		} else { // `end` is less than a page length from the global maximum index
 			// slide the page data area directly to the end
-			m.pageData = m.Data[len(m.Data)-m.PerPage : len(m.Data)]
+			m.pageData = m.items[len(m.items)-m.perPage : len(m.items)]
 			// `sliding distance` = `position after sliding` - `position before sliding`
 			// the global real-time index should also synchronize the same sliding distance
-			m.index += len(m.Data) - pageEnd
+			m.index += len(m.items) - pageEnd
 		}
 	}
 }
@@ -276,25 +386,25 @@ func (m *Model) nextPage() {
 // prePage triggers the page-up action, and does not change
 // the real-time page index(pageIndex)
 func (m *Model) prePage() {
-	// Get the start and end position of the page data area slice: m.Data[start:end]
+	// Get the start and end position of the page data area slice: m.items[start:end]
 	//
 	// note: the slice is closed left and opened right: `[start,end)`
 	//       assuming that the global data area has unlimited length,
 	//       end should always be the actual page `length+1`,
-	//       the maximum value of end should be equal to `len(m.Data)`
+	//       the maximum value of end should be equal to `len(m.items)`
 	//       under limited length
 	pageStart, pageEnd := m.pageIndexInfo()
 	// there are two cases when `start` does not reach the minimum value
 	if pageStart > 0 {
 		// `start` is at least one page length from the minimum
-		if pageStart >= m.PerPage {
+		if pageStart >= m.perPage {
 			// slide the page data area forward one page
-			m.pageData = m.Data[pageStart-m.PerPage : pageEnd-m.PerPage]
+			m.pageData = m.items[pageStart-m.perPage : pageEnd-m.perPage]
 			// Global real-time index reduces the length of one page
-			m.index -= m.PerPage
+			m.index -= m.perPage
 		} else { // `start` to the minimum value less than one page length
 			// slide the page data area directly to the start
-			m.pageData = m.Data[:m.PerPage]
+			m.pageData = m.items[:m.perPage]
 			// `sliding distance` = `position before sliding` - `minimum value(0)`
 			// the global real-time index should also synchronize the same sliding distance
 			m.index -= pageStart - 0
@@ -305,12 +415,13 @@ func (m *Model) prePage() {
 // forward triggers a fast jump action, if the pageIndex
 // is invalid, keep it as it is
 func (m *Model) forward(pageIndex string) {
-	// the caller guarantees that pageIndex is an integer, and err is not processed here
+	// if pageIndex is not an integer, idx will be -1 and ignored below
 	idx, _ := strconv.Atoi(pageIndex)
 	idx--
 
-	// pageIndex has exceeded the maximum index of the page, ignore
-	if idx > m.pageMaxIndex {
+	// pageIndex has exceeded the maximum index of the page or is
+	// not a number(the Jump binding is bound to other keys), ignore
+	if idx < 0 || idx > m.pageMaxIndex {
 		return
 	}
 
@@ -323,49 +434,180 @@ func (m *Model) forward(pageIndex string) {
 
 }
 
+// toggle switches the checked state of the item corresponding to the global index
+func (m *Model) toggle(index int) {
+	if m.checked[index] {
+		delete(m.checked, index)
+		return
+	}
+	m.checked[index] = true
+}
+
+// checkAll checks all items in the filtered data set
+func (m *Model) checkAll() {
+	for _, i := range m.origins {
+		m.checked[i] = true
+	}
+}
+
+// uncheckAll unchecks all items in the global data area
+func (m *Model) uncheckAll() {
+	m.checked = make(map[int]bool)
+}
+
+// updateFilter handles the filter input in the filter mode
+func (m *Model) updateFilter(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyRunes:
+		if msg.Alt {
+			return
+		}
+		m.filter += string(msg.Runes)
+	case tea.KeyBackspace:
+		if m.filter == "" {
+			return
+		}
+		r := []rune(m.filter)
+		m.filter = string(r[:len(r)-1])
+	default:
+		return
+	}
+	m.applyFilter()
+}
+
+// applyFilter filters the global data area with the filter input, and resets
+// the page data area and indexes according to the filtered data set; the cursor
+// stays on the same item if it still matches, otherwise it moves to the top
+func (m *Model) applyFilter() {
+	current := -1
+	if len(m.origins) > 0 {
+		current = m.origins[m.index]
+	}
+	m.items = m.items[:0]
+	m.origins = m.origins[:0]
+	if m.filter == "" {
+		m.items = append(m.items, m.Data...)
+		for i := range m.Data {
+			m.origins = append(m.origins, i)
+		}
+	} else {
+		type match struct {
+			index int
+			score int
+		}
+		var matches []match
+		for i, obj := range m.Data {
+			if _, score, ok := common.FuzzyMatch(m.filter, m.FilterFunc(obj)); ok {
+				matches = append(matches, match{index: i, score: score})
+			}
+		}
+		// the better matched items are displayed first, and the items with
+		// the same score keep the order of the global data area
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].score > matches[j].score
+		})
+		for _, mt := range matches {
+			m.items = append(m.items, m.Data[mt.index])
+			m.origins = append(m.origins, mt.index)
+		}
+	}
+
+	m.perPage = m.PerPage
+	if m.perPage > len(m.items) {
+		m.perPage = len(m.items)
+	}
+	m.pageMaxIndex = m.perPage - 1
+	m.maxIndex = len(m.items) - 1
+
+	m.index = 0
+	for i, origin := range m.origins {
+		if origin == current {
+			m.index = i
+			break
+		}
+	}
+	// keep the cursor on the same line of the page if possible, the
+	// page data area must not exceed the end of the filtered data set
+	if m.pageIndex > m.index {
+		m.pageIndex = m.index
+	}
+	if m.pageIndex > m.pageMaxIndex {
+		m.pageIndex = m.pageMaxIndex
+	}
+	if start := m.index - m.pageIndex; start+m.perPage > len(m.items) {
+		m.pageIndex = m.index - (len(m.items) - m.perPage)
+	}
+	if m.pageIndex < 0 {
+		m.pageIndex = 0
+	}
+	start := m.index - m.pageIndex
+	m.pageData = m.items[start : start+m.perPage]
+}
+
 // initData initialize the data model, set the default value and
 // fix the wrong parameter settings during initialization
 func (m *Model) initData() {
 	if m.PerPage > len(m.Data) || m.PerPage < 1 {
 		m.PerPage = len(m.Data)
-		m.pageData = m.Data
-	} else {
-		m.pageData = m.Data[:m.PerPage]
 	}
-
-	m.pageIndex = 0
-	m.pageMaxIndex = m.PerPage - 1
-	m.index = 0
-	m.maxIndex = len(m.Data) - 1
-	if m.HeaderFunc == nil {
-		m.HeaderFunc = func(_ Model, _ interface{}, _ int) string {
-			return common.FontColor(DefaultHeader, ColorHeader)
+	if m.FilterFunc == nil {
+		m.FilterFunc = func(obj interface{}) string {
+			return fmt.Sprint(obj)
+		}
+	}
+	if m.FilterPrompt == "" {
+		m.FilterPrompt = DefaultFilterPrompt
+	}
+	if m.KeyMap == nil {
+		km := DefaultKeyMap()
+		if m.Filterable {
+			km = km.withoutRuneKeys()
 		}
+		m.KeyMap = &km
+	}
+	m.applyFilter()
+	m.checked = make(map[int]bool)
+	m.help.Theme = m.Theme
+	m.help.Profile = m.ColorProfile
+	if m.HeaderFunc == nil {
+		m.HeaderFunc = defaultHeaderFunc
 	}
 	if m.Cursor == "" {
 		m.Cursor = DefaultCursor
 	}
-	if m.CursorColor == "" {
-		m.CursorColor = ColorCursor
+	// the cursor color of the theme is only the default without Styles
+	if m.CursorColor == "" && m.Styles == nil {
+		m.CursorColor = m.CurrentTheme().Cursor
+	}
+	if m.CheckedMark == "" {
+		m.CheckedMark = DefaultChecked
+	}
+	if m.UnCheckedMark == "" {
+		m.UnCheckedMark = DefaultUnChecked
 	}
 	if m.SelectedFunc == nil {
 		m.SelectedFunc = func(m Model, obj interface{}, gdIndex int) string {
-			return common.FontColor(fmt.Sprint(obj), ColorSelected)
+			return m.renderItem(StyleSelected, obj)
 		}
 	}
 	if m.UnSelectedFunc == nil {
 		m.UnSelectedFunc = func(m Model, obj interface{}, gdIndex int) string {
-			return common.FontColor(fmt.Sprint(obj), ColorUnSelected)
+			return m.renderItem(StyleUnSelected, obj)
 		}
 	}
 	if m.FooterFunc == nil {
-		m.FooterFunc = func(_ Model, _ interface{}, _ int) string {
-			return common.FontColor(DefaultFooter, ColorFooter)
-		}
+		m.FooterFunc = defaultFooterFunc
 	}
 	if m.FinishedFunc == nil {
 		m.FinishedFunc = func(s interface{}) string {
-			return common.FontColor(fmt.Sprintf(DefaultFinished, s), ColorFinished)
+			if choices, ok := s.([]Choice); ok {
+				values := make([]string, 0, len(choices))
+				for _, c := range choices {
+					values = append(values, fmt.Sprint(c.Value))
+				}
+				return m.Render(StyleFinished, fmt.Sprintf(DefaultFinished, strings.Join(values, ", ")))
+			}
+			return m.Render(StyleFinished, fmt.Sprintf(DefaultFinished, s))
 		}
 	}
 	m.init = true
@@ -377,15 +619,52 @@ func (m *Model) pageIndexInfo() (start, end int) {
 	// `Global real-time index` - `page real-time index` = `start index of page data area`
 	start = m.index - m.pageIndex
 	// `Page data area start index` + `single page size` = `page data area end index`
-	end = start + m.PerPage
+	end = start + m.perPage
 	return
 }
 
+// defaultHeaderFunc is the default HeaderFunc
+func defaultHeaderFunc(m Model, _ interface{}, _ int) string {
+	return m.Render(StyleHeader, m.navigationHeader())
+}
+
+// navigationHeader returns the default header text, the navigation keys
+// are read from the KeyMap, so the rebound keys are displayed
+func (m Model) navigationHeader() string {
+	if m.KeyMap == nil {
+		return DefaultHeader
+	}
+	var keys []string
+	for _, b := range []common.Binding{m.KeyMap.Down, m.KeyMap.Up, m.KeyMap.NextPage, m.KeyMap.PrePage} {
+		if b.Enabled() && b.Help().Key != "" {
+			keys = append(keys, b.Help().Key)
+		}
+	}
+	return fmt.Sprintf(DefaultHeaderFormat, strings.Join(keys, " "))
+}
+
+// defaultFooterFunc is the default FooterFunc
+func defaultFooterFunc(m Model, _ interface{}, _ int) string {
+	return m.Render(StyleFooter, DefaultFooter)
+}
+
 // DefaultHeaderFuncWithAppend return the default HeaderFunc and append
 // the given string to the next line of the default header
 func DefaultHeaderFuncWithAppend(append string) func(m Model, obj interface{}, gdIndex int) string {
 	return func(m Model, obj interface{}, gdIndex int) string {
-		return common.FontColor(DefaultHeader+"\n"+append, ColorHeader)
+		return m.Render(StyleHeader, m.navigationHeader()+"\n"+append)
+	}
+}
+
+// DefaultHeaderFuncWithHelp return a HeaderFunc that renders the help view of the
+// active key bindings instead of the static default header, and appends the given
+// string to the next line
+func DefaultHeaderFuncWithHelp(append string) func(m Model, obj interface{}, gdIndex int) string {
+	return func(m Model, obj interface{}, gdIndex int) string {
+		if append == "" {
+			return m.HelpView()
+		}
+		return m.HelpView() + "\n" + m.Render(StyleHeader, append)
 	}
 }
 
@@ -393,7 +672,7 @@ func DefaultHeaderFuncWithAppend(append string) func(m Model, obj interface{}, g
 // the serial number prefix of the given format
 func DefaultSelectedFuncWithIndex(indexFormat string) func(m Model, obj interface{}, gdIndex int) string {
 	return func(m Model, obj interface{}, gdIndex int) string {
-		return common.FontColor(fmt.Sprintf(indexFormat+" %v", gdIndex+1, obj), ColorSelected)
+		return m.Render(StyleSelected, fmt.Sprintf(indexFormat+" %v", gdIndex+1, obj))
 	}
 }
 
@@ -401,13 +680,47 @@ func DefaultSelectedFuncWithIndex(indexFormat string) func(m Model, obj interfac
 // adds the serial number prefix of the given format
 func DefaultUnSelectedFuncWithIndex(indexFormat string) func(m Model, obj interface{}, gdIndex int) string {
 	return func(m Model, obj interface{}, gdIndex int) string {
-		return common.FontColor(fmt.Sprintf(indexFormat+" %v", gdIndex+1, obj), ColorUnSelected)
+		return m.Render(StyleUnSelected, fmt.Sprintf(indexFormat+" %v", gdIndex+1, obj))
+	}
+}
+
+// HighlightMatches renders the given string with the given color, and highlights
+// the runes matched by the filter input, it is useful in the render functions
+func (m Model) HighlightMatches(s, color string) string {
+	matched, _, _ := common.FuzzyMatch(m.filter, s)
+	return m.highlightRunes(s, color, matched)
+}
+
+// highlightRunes renders the given string with the given color, and highlights
+// the runes at the given indexes
+func (m Model) highlightRunes(s, color string, matched []int) string {
+	if len(matched) == 0 {
+		return m.FontColor(s, color)
 	}
+
+	var b strings.Builder
+	runes := []rune(s)
+	for i, j := 0, 0; i < len(runes); i++ {
+		if j < len(matched) && matched[j] == i {
+			b.WriteString(m.FontColor(string(runes[i]), m.CurrentTheme().Matched))
+			j++
+			continue
+		}
+		b.WriteString(m.FontColor(string(runes[i]), color))
+	}
+	return b.String()
 }
 
-// Index return the global real time index
+// Index return the global real time index, in the filter mode it is
+// the index of the selected data in Data, and -1 if nothing is matched
 func (m Model) Index() int {
-	return m.index
+	if !m.init {
+		return m.index
+	}
+	if len(m.items) == 0 {
+		return -1
+	}
+	return m.origins[m.index]
 }
 
 // PageIndex return the real time index of the page
@@ -420,9 +733,37 @@ func (m Model) PageData() []interface{} {
 	return m.pageData
 }
 
-// Selected return the currently selected data
+// Selected return the currently selected data, it returns nil if
+// the filter matches nothing
 func (m Model) Selected() interface{} {
-	return m.Data[m.index]
+	idx := m.Index()
+	if idx < 0 {
+		return nil
+	}
+	return m.Data[idx]
+}
+
+// Filter return the current filter input
+func (m Model) Filter() string {
+	return m.filter
+}
+
+// Checked return whether the data corresponding to the global index is
+// checked in the multi-selection mode
+func (m Model) Checked(gdIndex int) bool {
+	return m.checked[gdIndex]
+}
+
+// Choices return the checked items and their global indexes in Data order,
+// it is only meaningful in the multi-selection mode
+func (m Model) Choices() []Choice {
+	var choices []Choice
+	for i, obj := range m.Data {
+		if m.checked[i] {
+			choices = append(choices, Choice{Index: i, Value: obj})
+		}
+	}
+	return choices
 }
 
 //// PageSelected return the currently selected data(same as the Selected func)
@@ -434,3 +775,33 @@ func (m Model) Selected() interface{} {
 func (m Model) Canceled() bool {
 	return m.canceled
 }
+
+// FontColor sets the color of the given string and bolds the font with the
+// color profile of the selector, the custom render functions can use it
+func (m Model) FontColor(str, color string) string {
+	return common.FontColorWithProfile(common.ProfileOrDefault(m.ColorProfile), str, color)
+}
+
+// Color sets the color of the given string without bolding the font with the
+// color profile of the selector, the custom render functions can use it
+func (m Model) Color(str, color string) string {
+	return common.ColorWithProfile(common.ProfileOrDefault(m.ColorProfile), str, color)
+}
+
+// renderCursor renders the cursor, CursorColor overrides the color of the cursor style
+func (m Model) renderCursor() string {
+	if m.Styles == nil {
+		return m.FontColor(m.Cursor, m.CursorColor)
+	}
+	style := m.Styles.Cursor
+	if m.CursorColor != "" {
+		style = style.Copy().Foreground(lipgloss.Color(m.CursorColor))
+	}
+	return common.RenderStyle(common.ProfileOrDefault(m.ColorProfile), style, m.Cursor)
+}
+
+// CurrentTheme return the theme used by the selector, it is the default theme
+// if Theme is not set; the custom render functions can read the colors from it
+func (m Model) CurrentTheme() common.Theme {
+	return common.ThemeOrDefault(m.Theme)
+}
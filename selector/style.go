@@ -0,0 +1,175 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mritd/bubbles/common"
+)
+
+// StyleKind identifies the element of the selector rendered by a style
+type StyleKind int
+
+const (
+	StyleHeader StyleKind = iota
+	StyleCursor
+	StyleSelected
+	StyleUnSelected
+	StyleFooter
+	StyleChecked
+	StyleMatched
+	StyleFinished
+)
+
+// Styles are the lipgloss styles of the selector elements, they support the
+// borders, padding, background, underline and width alignment; the default
+// render functions and the custom render functions(through Model.Render) use them
+type Styles struct {
+	Header     lipgloss.Style
+	Cursor     lipgloss.Style
+	Selected   lipgloss.Style
+	UnSelected lipgloss.Style
+	Footer     lipgloss.Style
+	Checked    lipgloss.Style
+	Matched    lipgloss.Style
+	Finished   lipgloss.Style
+}
+
+// DefaultStyles returns the styles equivalent to the default look of the
+// selector with the given theme, it is a starting point for customization
+func DefaultStyles(theme common.Theme) Styles {
+	style := func(color string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true)
+	}
+	return Styles{
+		Header:     style(theme.Header),
+		Cursor:     style(theme.Cursor),
+		Selected:   style(theme.Selected),
+		UnSelected: style(theme.UnSelected),
+		Footer:     style(theme.Footer),
+		Checked:    style(theme.Checked),
+		Matched:    style(theme.Matched),
+		Finished:   style(theme.Finished),
+	}
+}
+
+// style returns the style of the given element
+func (s Styles) style(kind StyleKind) lipgloss.Style {
+	switch kind {
+	case StyleHeader:
+		return s.Header
+	case StyleCursor:
+		return s.Cursor
+	case StyleSelected:
+		return s.Selected
+	case StyleUnSelected:
+		return s.UnSelected
+	case StyleFooter:
+		return s.Footer
+	case StyleChecked:
+		return s.Checked
+	case StyleMatched:
+		return s.Matched
+	default:
+		return s.Finished
+	}
+}
+
+// themeColor returns the theme color of the given element
+func (m Model) themeColor(kind StyleKind) string {
+	theme := m.CurrentTheme()
+	switch kind {
+	case StyleHeader:
+		return theme.Header
+	case StyleCursor:
+		return theme.Cursor
+	case StyleSelected:
+		return theme.Selected
+	case StyleUnSelected:
+		return theme.UnSelected
+	case StyleFooter:
+		return theme.Footer
+	case StyleChecked:
+		return theme.Checked
+	case StyleMatched:
+		return theme.Matched
+	default:
+		return theme.Finished
+	}
+}
+
+// Render renders the string as the given element, it uses the lipgloss style
+// of Styles if set, otherwise the theme color with the bold font
+func (m Model) Render(kind StyleKind, str string) string {
+	if m.Styles == nil {
+		return m.FontColor(str, m.themeColor(kind))
+	}
+	return common.RenderStyle(common.ProfileOrDefault(m.ColorProfile), m.Styles.style(kind), str)
+}
+
+// RenderMatches renders the string as the given element like Render, and
+// highlights the runes matched by the filter input with the StyleMatched style
+func (m Model) RenderMatches(kind StyleKind, s string) string {
+	matched, _, _ := common.FuzzyMatch(m.filter, s)
+	return m.renderRunes(kind, s, matched)
+}
+
+// renderItem renders the item as the given element for the default render functions,
+// the filter is matched against the FilterFunc string of the item, so the matched runes
+// are only highlighted if that string is found in the displayed string
+func (m Model) renderItem(kind StyleKind, obj interface{}) string {
+	s := fmt.Sprint(obj)
+	key := m.FilterFunc(obj)
+	matched, _, ok := common.FuzzyMatch(m.filter, key)
+	if !ok || len(matched) == 0 {
+		return m.Render(kind, s)
+	}
+	if key != s {
+		pos := strings.Index(s, key)
+		if pos < 0 {
+			return m.Render(kind, s)
+		}
+		offset := utf8.RuneCountInString(s[:pos])
+		for i := range matched {
+			matched[i] += offset
+		}
+	}
+	return m.renderRunes(kind, s, matched)
+}
+
+// renderRunes renders the string as the given element like Render, and
+// highlights the runes at the given indexes with the StyleMatched style
+func (m Model) renderRunes(kind StyleKind, s string, matched []int) string {
+	if m.Styles == nil {
+		return m.highlightRunes(s, m.themeColor(kind), matched)
+	}
+	if len(matched) == 0 {
+		return m.Render(kind, s)
+	}
+
+	// each segment is styled inline, so the reset of a highlighted rune does not
+	// clear the style of the following runes, then the whole line gets the block style
+	style := m.Styles.style(kind)
+	plain := style.Copy().Inline(true).UnsetWidth().UnsetAlign()
+	highlight := m.Styles.Matched.Copy().Inline(true).UnsetWidth().UnsetAlign()
+	var b, seg strings.Builder
+	runes := []rune(s)
+	for i, j := 0, 0; i < len(runes); i++ {
+		if j < len(matched) && matched[j] == i {
+			if seg.Len() > 0 {
+				b.WriteString(plain.Render(seg.String()))
+				seg.Reset()
+			}
+			b.WriteString(highlight.Render(string(runes[i])))
+			j++
+			continue
+		}
+		seg.WriteRune(runes[i])
+	}
+	if seg.Len() > 0 {
+		b.WriteString(plain.Render(seg.String()))
+	}
+	return common.RenderStyle(common.ProfileOrDefault(m.ColorProfile), style, b.String())
+}
@@ -0,0 +1,165 @@
+package selector
+
+import (
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mritd/bubbles/common"
+)
+
+// TypedChoice is a checked item of TypedModel in the multi-selection mode
+type TypedChoice[T any] struct {
+	// Index is the global index of the item in Data
+	Index int
+	// Value is the checked item
+	Value T
+}
+
+// TypedModel is the type-safe variant of Model, Data is a []T and the render
+// functions receive the item as T, so there is no need to assert the type of
+// the item in the render functions.
+//
+// The options that do not depend on the item type (PerPage, Cursor, MultiSelect...)
+// are set through the embedded Model; the embedded Model.Data must not be set,
+// it is populated from TypedModel.Data during initialization. The render functions
+// that are not set fall back to the ones of the embedded Model, so the Default*
+// helpers of Model can still be used there.
+type TypedModel[T any] struct {
+	Model
+
+	// HeaderFunc Header rendering function
+	HeaderFunc func(m TypedModel[T], obj T, gdIndex int) string
+	// SelectedFunc selected data rendering function
+	SelectedFunc func(m TypedModel[T], obj T, gdIndex int) string
+	// UnSelectedFunc unselected data rendering function
+	UnSelectedFunc func(m TypedModel[T], obj T, gdIndex int) string
+	// FooterFunc footer rendering function
+	FooterFunc func(m TypedModel[T], obj T, gdIndex int) string
+	// FinishedFunc finished rendering function
+	FinishedFunc func(selected T) string
+	// FinishedChoicesFunc finished rendering function in the multi-selection mode
+	FinishedChoicesFunc func(choices []TypedChoice[T]) string
+	// FilterFunc returns the string used to match the filter for each item
+	FilterFunc func(obj T) string
+	// Data the data set to be rendered
+	Data []T
+}
+
+// Update method responds to various events and modifies the data model
+// according to the corresponding events
+func (m *TypedModel[T]) Update(msg tea.Msg) (*TypedModel[T], tea.Cmd) {
+	if !m.Model.init {
+		m.initTypedData()
+	}
+
+	_, cmd := m.Model.Update(msg)
+	return m, cmd
+}
+
+// initTypedData copies the typed data set to the embedded Model, and adapts
+// the typed render functions to the functions of the embedded Model
+func (m *TypedModel[T]) initTypedData() {
+	m.Model.Data = make([]interface{}, len(m.Data))
+	for i, obj := range m.Data {
+		m.Model.Data[i] = obj
+	}
+
+	// the render functions receive a copy of the typed model that carries
+	// the real time state of the embedded Model
+	base := *m
+	typed := func(mm Model) TypedModel[T] {
+		tm := base
+		tm.Model = mm
+		return tm
+	}
+	wrap := func(f func(m TypedModel[T], obj T, gdIndex int) string) func(m Model, obj interface{}, gdIndex int) string {
+		return func(mm Model, obj interface{}, gdIndex int) string {
+			return f(typed(mm), cast[T](obj), gdIndex)
+		}
+	}
+
+	if m.HeaderFunc != nil {
+		m.Model.HeaderFunc = wrap(m.HeaderFunc)
+	}
+	if m.SelectedFunc != nil {
+		m.Model.SelectedFunc = wrap(m.SelectedFunc)
+	}
+	if m.UnSelectedFunc != nil {
+		m.Model.UnSelectedFunc = wrap(m.UnSelectedFunc)
+	}
+	if m.FooterFunc != nil {
+		m.Model.FooterFunc = wrap(m.FooterFunc)
+	}
+	if m.MultiSelect && m.FinishedChoicesFunc != nil {
+		f := m.FinishedChoicesFunc
+		m.Model.FinishedFunc = func(s interface{}) string {
+			choices, _ := s.([]Choice)
+			return f(typedChoices[T](choices))
+		}
+	}
+	if !m.MultiSelect && m.FinishedFunc != nil {
+		f := m.FinishedFunc
+		m.Model.FinishedFunc = func(s interface{}) string {
+			return f(cast[T](s))
+		}
+	}
+	if m.FilterFunc != nil {
+		f := m.FilterFunc
+		m.Model.FilterFunc = func(obj interface{}) string {
+			return f(cast[T](obj))
+		}
+	}
+}
+
+// Selected return the currently selected data, it returns the zero
+// value of T if the filter matches nothing
+func (m TypedModel[T]) Selected() T {
+	return cast[T](m.Model.Selected())
+}
+
+// PageData return the current page data area slice
+func (m TypedModel[T]) PageData() []T {
+	data := make([]T, 0, len(m.Model.PageData()))
+	for _, obj := range m.Model.PageData() {
+		data = append(data, cast[T](obj))
+	}
+	return data
+}
+
+// Choices return the checked items and their global indexes in Data order,
+// it is only meaningful in the multi-selection mode
+func (m TypedModel[T]) Choices() []TypedChoice[T] {
+	return typedChoices[T](m.Model.Choices())
+}
+
+// typedChoices converts the untyped choices to the typed choices
+func typedChoices[T any](choices []Choice) []TypedChoice[T] {
+	var typed []TypedChoice[T]
+	for _, c := range choices {
+		typed = append(typed, TypedChoice[T]{Index: c.Index, Value: cast[T](c.Value)})
+	}
+	return typed
+}
+
+// cast converts the untyped item to T, the nil item (e.g. Selected when
+// the filter matches nothing) is converted to the zero value
+func cast[T any](obj interface{}) T {
+	v, _ := obj.(T)
+	return v
+}
+
+// Run runs the selector until the selection finishes, see Model.Run
+func (m *TypedModel[T]) Run() error {
+	return common.RunProgram(func(msg tea.Msg) tea.Cmd {
+		_, cmd := m.Update(msg)
+		return cmd
+	}, func() string { return m.View() }, m.RunPlain)
+}
+
+// RunPlain runs the selector in the plain-text mode, see Model.RunPlain
+func (m *TypedModel[T]) RunPlain(in io.Reader, out io.Writer) error {
+	if !m.Model.init {
+		m.initTypedData()
+	}
+	return m.Model.RunPlain(in, out)
+}